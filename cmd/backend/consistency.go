@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/status-app/internal/projections"
+)
+
+const (
+	// paramMinPosition asks a query to reflect every event up to a position,
+	// typically the one a command response returned
+	paramMinPosition = "min_position"
+
+	// minPositionTimeout bounds how long a query waits for the read models
+	minPositionTimeout = 5 * time.Second
+
+	// headerReadModelsIncomplete marks a min_position response served although
+	// an event up to the position was dead-lettered instead of applied
+	headerReadModelsIncomplete = "X-Read-Models-Incomplete"
+)
+
+// positionWaiter waits for the read models to reach a log position
+type positionWaiter interface {
+	WaitForPosition(ctx context.Context, position int64, timeout time.Duration) error
+}
+
+// withMinPosition gives queries read-your-writes consistency: a request with
+// ?min_position=N is answered only once the read models have applied the event
+// at position N. If they do not catch up within minPositionTimeout, the
+// request fails with 503 and can be retried. If an event up to N was
+// dead-lettered, the read models may never reflect it and retrying would not
+// help, so the request is answered with X-Read-Models-Incomplete: true. The
+// dead letter may belong to an unrelated team, so the read is not failed.
+func withMinPosition(waiter positionWaiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			value := r.URL.Query().Get(paramMinPosition)
+			if value == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			position, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || position < 0 {
+				jsonError(w, "min_position must be a non-negative integer", http.StatusBadRequest)
+				return
+			}
+
+			if err := waiter.WaitForPosition(r.Context(), position, minPositionTimeout); err != nil {
+				if errors.Is(err, projections.ErrPositionNotReached) {
+					w.Header().Set("Retry-After", "1")
+					jsonError(w, fmt.Sprintf("read models have not reached position %d yet", position), http.StatusServiceUnavailable)
+					return
+				}
+				if !errors.Is(err, projections.ErrPositionDeadLettered) {
+					jsonError(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set(headerReadModelsIncomplete, "true")
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
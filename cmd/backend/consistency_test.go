@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yourusername/status-app/internal/projections"
+)
+
+// fakeWaiter reports the read models at a fixed position, with the event at
+// deadLettered (if set) dead-lettered
+type fakeWaiter struct {
+	position     int64
+	deadLettered int64
+	waitedOn     int64
+}
+
+func (f *fakeWaiter) WaitForPosition(ctx context.Context, position int64, timeout time.Duration) error {
+	f.waitedOn = position
+	if position > f.position {
+		return fmt.Errorf("%w: at %d", projections.ErrPositionNotReached, f.position)
+	}
+	if f.deadLettered > 0 && position >= f.deadLettered {
+		return fmt.Errorf("%w: event at %d is dead-lettered", projections.ErrPositionDeadLettered, f.deadLettered)
+	}
+	return nil
+}
+
+func TestWithMinPosition(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantWait   int64
+	}{
+		{"no min_position", "", http.StatusOK, 0},
+		{"reached", "?min_position=8", http.StatusOK, 8},
+		{"dead-lettered", "?min_position=10", http.StatusOK, 10},
+		{"not reached", "?min_position=11", http.StatusServiceUnavailable, 11},
+		{"invalid", "?min_position=abc", http.StatusBadRequest, 0},
+		{"negative", "?min_position=-1", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waiter := &fakeWaiter{position: 10, deadLettered: 9}
+			handler := withMinPosition(waiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/teams"+tt.query, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if waiter.waitedOn != tt.wantWait {
+				t.Errorf("waited on %d, want %d", waiter.waitedOn, tt.wantWait)
+			}
+			if incomplete := rec.Header().Get(headerReadModelsIncomplete) == "true"; incomplete != (tt.name == "dead-lettered") {
+				t.Errorf("%s = %v, want it only past the dead letter", headerReadModelsIncomplete, incomplete)
+			}
+			if tt.wantStatus == http.StatusServiceUnavailable && rec.Header().Get("Retry-After") == "" {
+				t.Error("expected Retry-After on 503")
+			}
+		})
+	}
+}
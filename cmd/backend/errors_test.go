@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/status-app/internal/domain"
+	"github.com/yourusername/status-app/internal/events"
+	"github.com/yourusername/status-app/internal/projections"
+)
+
+func TestCommandError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{
+			name:     "concurrency conflict",
+			err:      fmt.Errorf("failed to auto-register team: %w", events.ErrConcurrencyConflict),
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "unknown team",
+			err:      fmt.Errorf("%w: team-1", domain.ErrTeamNotFound),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "unchanged team",
+			err:      fmt.Errorf("%w: new team name is the same as current name", domain.ErrTeamUnchanged),
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "unknown update",
+			err:      fmt.Errorf("%w: update-1", domain.ErrUpdateNotFound),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "unchanged update",
+			err:      fmt.Errorf("%w: content is the same as the current content", domain.ErrUpdateUnchanged),
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "structured update edit",
+			err:      fmt.Errorf("%w: update-1", domain.ErrUpdateStructured),
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "invalid payload",
+			err:      fmt.Errorf("failed to submit status update: %w", events.ErrInvalidPayload),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unexpected error",
+			err:      errors.New("database unavailable"),
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			commandError(rec, tt.err)
+			if rec.Code != tt.wantCode {
+				t.Errorf("commandError() status = %d, want %d", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestDeadLetterError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{
+			name:     "not found",
+			err:      projections.ErrDeadLetterNotFound,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "retry failed",
+			err:      errors.New("insert violates foreign key constraint"),
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			deadLetterError(rec, tt.err)
+			if rec.Code != tt.wantCode {
+				t.Errorf("deadLetterError() status = %d, want %d", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
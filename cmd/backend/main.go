@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -20,6 +22,7 @@ import (
 	"github.com/yourusername/status-app/internal/config"
 	"github.com/yourusername/status-app/internal/domain"
 	"github.com/yourusername/status-app/internal/events"
+	"github.com/yourusername/status-app/internal/idempotency"
 	"github.com/yourusername/status-app/internal/projections"
 )
 
@@ -28,9 +31,68 @@ type SubmitStatusUpdateRequest struct {
 	Content     string `json:"content"`
 	Author      string `json:"author"`
 	ChannelName string `json:"channel_name"`
+
+	// SlackMessage is the message the update was posted as, set when the
+	// update came from Slack
+	SlackMessage *SlackMessageRequest `json:"slack_message,omitempty"`
+
+	// Sections makes the update structured; content is then rendered from
+	// them and may be omitted
+	Sections *UpdateSectionsRequest `json:"sections,omitempty"`
+}
+
+type UpdateSectionsRequest struct {
+	Done     string `json:"done,omitempty"`
+	Next     string `json:"next,omitempty"`
+	Blockers string `json:"blockers,omitempty"`
+	Health   string `json:"health,omitempty"` // green, amber or red
+}
+
+type SlackMessageRequest struct {
+	Channel   string `json:"channel"`
+	TS        string `json:"ts"`
+	ThreadTS  string `json:"thread_ts,omitempty"`
+	Permalink string `json:"permalink,omitempty"`
 }
 
 func (r *SubmitStatusUpdateRequest) Validate() error {
+	if r.Content == "" && r.Sections == nil {
+		return errors.New("content is required")
+	}
+	if len(r.Content) > 500 {
+		return errors.New("content must be 500 characters or less")
+	}
+	if r.Author == "" {
+		return errors.New("author is required")
+	}
+	return nil
+}
+
+type EditStatusUpdateRequest struct {
+	Content   string `json:"content"`
+	SlackUser string `json:"slack_user,omitempty"` // set when the edit came from Slack
+}
+
+func (r *EditStatusUpdateRequest) Validate() error {
+	if r.Content == "" {
+		return errors.New("content is required")
+	}
+	if len(r.Content) > 500 {
+		return errors.New("content must be 500 characters or less")
+	}
+	return nil
+}
+
+type CommentOnStatusUpdateRequest struct {
+	Content   string `json:"content"`
+	Author    string `json:"author"`
+	SlackUser string `json:"slack_user,omitempty"` // set when the comment came from Slack
+
+	// SlackMessage is the thread reply the comment was posted as
+	SlackMessage *SlackMessageRequest `json:"slack_message,omitempty"`
+}
+
+func (r *CommentOnStatusUpdateRequest) Validate() error {
 	if r.Content == "" {
 		return errors.New("content is required")
 	}
@@ -43,6 +105,26 @@ func (r *SubmitStatusUpdateRequest) Validate() error {
 	return nil
 }
 
+type ChangeTeamHealthRequest struct {
+	Health    string `json:"health"`
+	Reason    string `json:"reason,omitempty"`
+	SlackUser string `json:"slack_user,omitempty"` // set when the change came from Slack
+}
+
+func (r *ChangeTeamHealthRequest) Validate() error {
+	if r.Health == "" {
+		return errors.New("health is required")
+	}
+	if len(r.Reason) > 500 {
+		return errors.New("reason must be 500 characters or less")
+	}
+	return nil
+}
+
+type DeleteStatusUpdateRequest struct {
+	SlackUser string `json:"slack_user,omitempty"` // set when the deletion came from Slack
+}
+
 type RegisterTeamRequest struct {
 	Name         string `json:"name"`
 	SlackChannel string `json:"slack_channel"`
@@ -74,6 +156,9 @@ func main() {
 	}
 	defer eventStore.Close()
 
+	// Commands append through the registry so malformed payloads never reach the log
+	validatingStore := events.NewValidatingStore(eventStore, events.DefaultRegistry)
+
 	// Initialize projection database
 	projectionDB, err := sql.Open("postgres", cfg.ProjectionDBURL)
 	if err != nil {
@@ -81,8 +166,18 @@ func main() {
 	}
 	defer projectionDB.Close()
 
+	// Idempotency keys live alongside the events they guard against duplicating
+	idempotencyDB, err := sql.Open("postgres", cfg.EventStoreURL)
+	if err != nil {
+		log.Fatalf("Failed to open idempotency database: %v", err)
+	}
+	defer idempotencyDB.Close()
+	idempotencyStore := idempotency.NewPostgresStore(idempotencyDB)
+	go pruneIdempotencyKeys(ctx, idempotencyStore)
+	idempotent := idempotency.Middleware(idempotencyStore)
+
 	// Initialize command handler
-	cmdHandler := commands.NewHandler(eventStore)
+	cmdHandler := commands.NewHandlerWithSnapshots(validatingStore, eventStore.Snapshots())
 
 	// Initialize projection repository
 	repo := projections.NewRepository(projectionDB)
@@ -97,6 +192,8 @@ func main() {
 	}()
 	log.Println("Projections running in background")
 
+	rebuilder := projections.NewRebuilder(eventStore, projectionDB)
+
 	// Setup HTTP routes
 	mux := http.NewServeMux()
 
@@ -120,16 +217,37 @@ func main() {
 	log.Println("API authentication enabled")
 	protectedMux := http.NewServeMux()
 
+	// Queries accept ?min_position= to read their own writes
+	consistent := withMinPosition(repo)
+
 	// RESTful API endpoints
-	protectedMux.HandleFunc("POST /teams", handleRegisterTeam(cmdHandler))
-	protectedMux.HandleFunc("GET /teams", handleGetTeams(repo))
-	protectedMux.HandleFunc("GET /teams/{id}", handleGetTeam(repo))
+	protectedMux.Handle("POST /teams", idempotent(handleRegisterTeam(cmdHandler)))
+	protectedMux.Handle("GET /teams", consistent(handleGetTeams(repo)))
+	protectedMux.Handle("GET /teams/{id}", consistent(handleGetTeam(repo)))
 	protectedMux.HandleFunc("PATCH /teams/{id}", handleUpdateTeamName(cmdHandler, repo))
-	protectedMux.HandleFunc("POST /teams/{id}/updates", handleSubmitUpdate(cmdHandler))
-	protectedMux.HandleFunc("GET /teams/{id}/updates", handleGetTeamUpdates(repo))
-	protectedMux.HandleFunc("GET /updates", handleGetRecentUpdates(repo))
+	protectedMux.Handle("POST /teams/{id}/health", idempotent(handleChangeTeamHealth(cmdHandler)))
+	protectedMux.Handle("GET /teams/{id}/health", consistent(handleGetTeamHealth(repo)))
+	protectedMux.Handle("POST /teams/{id}/updates", idempotent(handleSubmitUpdate(cmdHandler)))
+	protectedMux.Handle("GET /teams/{id}/updates", consistent(handleGetTeamUpdates(repo)))
+	protectedMux.Handle("GET /teams/{id}/updates/{updateID}", consistent(handleGetUpdate(repo)))
+	protectedMux.HandleFunc("PATCH /teams/{id}/updates/{updateID}", handleEditUpdate(cmdHandler))
+	protectedMux.HandleFunc("DELETE /teams/{id}/updates/{updateID}", handleDeleteUpdate(cmdHandler))
+	protectedMux.Handle("GET /teams/{id}/updates/{updateID}/edits", consistent(handleGetUpdateEdits(repo)))
+	protectedMux.Handle("POST /teams/{id}/updates/{updateID}/comments", idempotent(handleCommentOnUpdate(cmdHandler)))
+	protectedMux.Handle("GET /teams/{id}/updates/{updateID}/comments", consistent(handleGetUpdateComments(repo)))
+	protectedMux.Handle("GET /updates", consistent(handleGetRecentUpdates(repo)))
+	protectedMux.Handle("GET /blockers", consistent(handleGetBlockedUpdates(repo)))
+	protectedMux.Handle("GET /portfolio", consistent(handleGetPortfolio(repo)))
+	protectedMux.HandleFunc("GET /events", handleGetEvents(eventStore))
+
+	// Admin endpoints
+	protectedMux.HandleFunc("GET /admin/dead-letters", handleGetDeadLetters(repo))
+	protectedMux.HandleFunc("POST /admin/dead-letters/{eventID}/retry", handleRetryDeadLetter(projector))
+	protectedMux.HandleFunc("POST /admin/dead-letters/{eventID}/skip", handleSkipDeadLetter(projector))
+	protectedMux.HandleFunc("POST /admin/projections/rebuild", handleRebuildProjections(ctx, rebuilder))
+	protectedMux.HandleFunc("GET /admin/projections/rebuild", handleGetRebuildStatus(rebuilder))
 
-	mux.Handle("/", auth.RequireAPIKey(cfg.APISecret)(protectedMux))
+	mux.Handle("/", auth.RequireAPIKey(cfg.APISecret)(withEventMetadata(protectedMux)))
 
 	server := &http.Server{
 		Addr:    ":" + cfg.Port,
@@ -156,6 +274,28 @@ func main() {
 	server.Shutdown(shutdownCtx)
 }
 
+// pruneIdempotencyKeys deletes expired idempotency keys every hour until ctx is done
+func pruneIdempotencyKeys(ctx context.Context, store *idempotency.PostgresStore) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := store.Prune(ctx, time.Now())
+			if err != nil {
+				log.Printf("Failed to prune idempotency keys: %v", err)
+				continue
+			}
+			if pruned > 0 {
+				log.Printf("Pruned %d expired idempotency keys", pruned)
+			}
+		}
+	}
+}
+
 // jsonError sends a JSON error response
 func jsonError(w http.ResponseWriter, message string, code int) {
 	w.Header().Set("Content-Type", "application/json")
@@ -163,6 +303,55 @@ func jsonError(w http.ResponseWriter, message string, code int) {
 	json.NewEncoder(w).Encode(map[string]string{"error": message})
 }
 
+// commandError maps command handler errors to HTTP status codes
+func commandError(w http.ResponseWriter, err error) {
+	if errors.Is(err, events.ErrConcurrencyConflict) {
+		jsonError(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, domain.ErrTeamNotFound) || errors.Is(err, domain.ErrUpdateNotFound) {
+		jsonError(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, domain.ErrTeamUnchanged) || errors.Is(err, domain.ErrTeamAlreadyRegistered) || errors.Is(err, domain.ErrUpdateUnchanged) || errors.Is(err, domain.ErrUpdateStructured) {
+		jsonError(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, events.ErrInvalidPayload) || errors.Is(err, events.ErrUnknownEventType) {
+		jsonError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	jsonError(w, err.Error(), http.StatusInternalServerError)
+}
+
+// commandResponse reports what a command created and the events it appended
+type commandResponse struct {
+	Status    string                   `json:"status"`
+	TeamID    string                   `json:"team_id"`
+	UpdateID  string                   `json:"update_id,omitempty"`
+	CommentID string                   `json:"comment_id,omitempty"`
+	Position  int64                    `json:"position"`
+	Events    []commands.AppendedEvent `json:"events"`
+}
+
+// writeCommandResult sends a command's result, pointing Location at the
+// resource it created if location is set
+func writeCommandResult(w http.ResponseWriter, code int, location string, result commands.Result) {
+	if location != "" {
+		w.Header().Set("Location", location)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(commandResponse{
+		Status:    "success",
+		TeamID:    result.TeamID.String(),
+		UpdateID:  result.UpdateID.String(),
+		CommentID: result.CommentID.String(),
+		Position:  result.Position(),
+		Events:    result.Events,
+	})
+}
+
 // Command handlers
 func handleSubmitUpdate(handler *commands.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -189,12 +378,22 @@ func handleSubmitUpdate(handler *commands.Handler) http.HandlerFunc {
 			return
 		}
 
-		content, err := domain.NewUpdateContent(req.Content)
+		sections, err := optionalSections(req.Sections)
 		if err != nil {
-			jsonError(w, fmt.Sprintf("invalid content: %v", err), http.StatusBadRequest)
+			jsonError(w, fmt.Sprintf("invalid sections: %v", err), http.StatusBadRequest)
 			return
 		}
 
+		// Structured updates render their content from the sections
+		var content domain.UpdateContent
+		if sections.IsZero() {
+			content, err = domain.NewUpdateContent(req.Content)
+			if err != nil {
+				jsonError(w, fmt.Sprintf("invalid content: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+
 		author, err := domain.NewAuthor(req.Author)
 		if err != nil {
 			jsonError(w, fmt.Sprintf("invalid author: %v", err), http.StatusBadRequest)
@@ -207,25 +406,31 @@ func handleSubmitUpdate(handler *commands.Handler) http.HandlerFunc {
 			return
 		}
 
+		message, err := optionalSlackMessage(req.SlackMessage)
+		if err != nil {
+			jsonError(w, fmt.Sprintf("invalid slack message: %v", err), http.StatusBadRequest)
+			return
+		}
+
 		cmd := commands.SubmitStatusUpdate{
-			TeamID:      teamID,
-			ChannelName: req.ChannelName,
-			Content:     content,
-			Author:      author,
-			SlackUser:   slackUser,
-			Timestamp:   time.Now(),
+			TeamID:       teamID,
+			ChannelName:  req.ChannelName,
+			Content:      content,
+			Author:       author,
+			SlackUser:    slackUser,
+			SlackMessage: message,
+			Sections:     sections,
+			Timestamp:    time.Now(),
 		}
 
-		if err := handler.Handle(r.Context(), cmd); err != nil {
-			jsonError(w, err.Error(), http.StatusInternalServerError)
+		result, err := handler.Handle(r.Context(), cmd)
+		if err != nil {
+			commandError(w, err)
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
-		json.NewEncoder(w).Encode(map[string]string{
-			"status": "success",
-		})
+		location := "/teams/" + result.TeamID.String() + "/updates/" + result.UpdateID.String()
+		writeCommandResult(w, http.StatusCreated, location, result)
 	}
 }
 
@@ -259,16 +464,13 @@ func handleRegisterTeam(handler *commands.Handler) http.HandlerFunc {
 			SlackChannel: channel,
 		}
 
-		if err := handler.Handle(r.Context(), cmd); err != nil {
-			jsonError(w, err.Error(), http.StatusInternalServerError)
+		result, err := handler.Handle(r.Context(), cmd)
+		if err != nil {
+			commandError(w, err)
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
-		json.NewEncoder(w).Encode(map[string]string{
-			"status": "success",
-		})
+		writeCommandResult(w, http.StatusCreated, "/teams/"+result.TeamID.String(), result)
 	}
 }
 
@@ -372,15 +574,225 @@ func handleUpdateTeamName(handler *commands.Handler, repo *projections.Repositor
 			SlackChannel: channel,
 		}
 
-		if err := handler.Handle(r.Context(), cmd); err != nil {
+		result, err := handler.Handle(r.Context(), cmd)
+		if err != nil {
+			commandError(w, err)
+			return
+		}
+
+		writeCommandResult(w, http.StatusOK, "", result)
+	}
+}
+
+func handleChangeTeamHealth(handler *commands.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := domain.NewTeamID(r.PathValue("id"))
+		if err != nil {
+			jsonError(w, fmt.Sprintf("invalid team ID: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		var req ChangeTeamHealthRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			jsonError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		health, err := domain.NewHealth(req.Health)
+		if err != nil {
+			jsonError(w, fmt.Sprintf("invalid health: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		changedBy, err := optionalSlackUser(req.SlackUser)
+		if err != nil {
+			jsonError(w, fmt.Sprintf("invalid slack user: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		cmd := commands.ChangeTeamHealth{
+			TeamID:    teamID,
+			Health:    health,
+			Reason:    req.Reason,
+			ChangedBy: changedBy,
+			Timestamp: time.Now(),
+		}
+
+		result, err := handler.Handle(r.Context(), cmd)
+		if err != nil {
+			commandError(w, err)
+			return
+		}
+
+		writeCommandResult(w, http.StatusCreated, "/teams/"+teamID.String()+"/health", result)
+	}
+}
+
+// handleGetTeamHealth returns a team's current health and the history of its changes
+func handleGetTeamHealth(repo *projections.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		health, err := repo.GetTeamHealth(r.Context(), r.PathValue("id"))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				jsonError(w, "team not found", http.StatusNotFound)
+				return
+			}
 			jsonError(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]string{
-			"status": "success",
-		})
+		json.NewEncoder(w).Encode(health)
+	}
+}
+
+// handleGetPortfolio lists all teams with the ones in trouble first
+func handleGetPortfolio(repo *projections.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teams, err := repo.GetTeamsByHealth(r.Context())
+		if err != nil {
+			jsonError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(teams)
+	}
+}
+
+// updatePath parses the team and update IDs of a /teams/{id}/updates/{updateID} request
+func updatePath(r *http.Request) (domain.TeamID, domain.UpdateID, error) {
+	teamID, err := domain.NewTeamID(r.PathValue("id"))
+	if err != nil {
+		return domain.TeamID{}, domain.UpdateID{}, fmt.Errorf("invalid team ID: %v", err)
+	}
+	updateID, err := domain.NewUpdateID(r.PathValue("updateID"))
+	if err != nil {
+		return domain.TeamID{}, domain.UpdateID{}, fmt.Errorf("invalid update ID: %v", err)
+	}
+	return teamID, updateID, nil
+}
+
+// optionalSlackUser parses a Slack user ID that may be omitted
+func optionalSlackUser(s string) (domain.SlackUserID, error) {
+	if s == "" {
+		return domain.SlackUserID{}, nil
+	}
+	return domain.NewSlackUserID(s)
+}
+
+// optionalSlackMessage parses a Slack message that may be omitted
+func optionalSlackMessage(req *SlackMessageRequest) (domain.SlackMessage, error) {
+	if req == nil {
+		return domain.SlackMessage{}, nil
+	}
+	return domain.NewSlackMessage(req.Channel, req.TS, req.ThreadTS, req.Permalink)
+}
+
+// optionalSections parses the sections of a structured update, which are
+// omitted for free-text updates
+func optionalSections(req *UpdateSectionsRequest) (domain.UpdateSections, error) {
+	if req == nil {
+		return domain.UpdateSections{}, nil
+	}
+	var health domain.Health
+	if req.Health != "" {
+		var err error
+		if health, err = domain.NewHealth(req.Health); err != nil {
+			return domain.UpdateSections{}, err
+		}
+	}
+	return domain.NewUpdateSections(req.Done, req.Next, req.Blockers, health)
+}
+
+func handleEditUpdate(handler *commands.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, updateID, err := updatePath(r)
+		if err != nil {
+			jsonError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req EditStatusUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			jsonError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		content, err := domain.NewUpdateContent(req.Content)
+		if err != nil {
+			jsonError(w, fmt.Sprintf("invalid content: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		editedBy, err := optionalSlackUser(req.SlackUser)
+		if err != nil {
+			jsonError(w, fmt.Sprintf("invalid slack user: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		cmd := commands.EditStatusUpdate{
+			TeamID:    teamID,
+			UpdateID:  updateID,
+			Content:   content,
+			EditedBy:  editedBy,
+			Timestamp: time.Now(),
+		}
+
+		result, err := handler.Handle(r.Context(), cmd)
+		if err != nil {
+			commandError(w, err)
+			return
+		}
+
+		writeCommandResult(w, http.StatusOK, "", result)
+	}
+}
+
+func handleDeleteUpdate(handler *commands.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, updateID, err := updatePath(r)
+		if err != nil {
+			jsonError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// The body is optional
+		var req DeleteStatusUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			jsonError(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		deletedBy, err := optionalSlackUser(req.SlackUser)
+		if err != nil {
+			jsonError(w, fmt.Sprintf("invalid slack user: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		cmd := commands.DeleteStatusUpdate{
+			TeamID:    teamID,
+			UpdateID:  updateID,
+			DeletedBy: deletedBy,
+			Timestamp: time.Now(),
+		}
+
+		result, err := handler.Handle(r.Context(), cmd)
+		if err != nil {
+			commandError(w, err)
+			return
+		}
+
+		writeCommandResult(w, http.StatusOK, "", result)
 	}
 }
 
@@ -396,6 +808,84 @@ func handleGetRecentUpdates(repo *projections.Repository) http.HandlerFunc {
 	}
 }
 
+// handleGetBlockedUpdates lists structured updates that report blockers,
+// across all teams
+func handleGetBlockedUpdates(repo *projections.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		updates, err := repo.GetBlockedUpdates(r.Context(), 50)
+		if err != nil {
+			jsonError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updates)
+	}
+}
+
+func handleCommentOnUpdate(handler *commands.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, updateID, err := updatePath(r)
+		if err != nil {
+			jsonError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req CommentOnStatusUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			jsonError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		content, err := domain.NewUpdateContent(req.Content)
+		if err != nil {
+			jsonError(w, fmt.Sprintf("invalid content: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		author, err := domain.NewAuthor(req.Author)
+		if err != nil {
+			jsonError(w, fmt.Sprintf("invalid author: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		slackUser, err := optionalSlackUser(req.SlackUser)
+		if err != nil {
+			jsonError(w, fmt.Sprintf("invalid slack user: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		message, err := optionalSlackMessage(req.SlackMessage)
+		if err != nil {
+			jsonError(w, fmt.Sprintf("invalid slack message: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		cmd := commands.CommentOnStatusUpdate{
+			TeamID:       teamID,
+			UpdateID:     updateID,
+			Content:      content,
+			Author:       author,
+			SlackUser:    slackUser,
+			SlackMessage: message,
+			Timestamp:    time.Now(),
+		}
+
+		result, err := handler.Handle(r.Context(), cmd)
+		if err != nil {
+			commandError(w, err)
+			return
+		}
+
+		location := "/teams/" + teamID.String() + "/updates/" + updateID.String() + "/comments"
+		writeCommandResult(w, http.StatusCreated, location, result)
+	}
+}
+
 func handleGetTeamUpdates(repo *projections.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		teamID := r.PathValue("id")
@@ -404,6 +894,22 @@ func handleGetTeamUpdates(repo *projections.Repository) http.HandlerFunc {
 			return
 		}
 
+		// slack_ts narrows the list to the update posted as that Slack message
+		if ts := r.URL.Query().Get("slack_ts"); ts != "" {
+			update, err := repo.GetUpdateBySlackTS(r.Context(), teamID, ts)
+			switch {
+			case err == sql.ErrNoRows:
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode([]*projections.StatusUpdate{})
+			case err != nil:
+				jsonError(w, err.Error(), http.StatusInternalServerError)
+			default:
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode([]*projections.StatusUpdate{update})
+			}
+			return
+		}
+
 		updates, err := repo.GetTeamUpdates(r.Context(), teamID, 50)
 		if err != nil {
 			jsonError(w, err.Error(), http.StatusInternalServerError)
@@ -414,3 +920,182 @@ func handleGetTeamUpdates(repo *projections.Repository) http.HandlerFunc {
 		json.NewEncoder(w).Encode(updates)
 	}
 }
+
+func handleGetUpdate(repo *projections.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		update, err := repo.GetUpdate(r.Context(), r.PathValue("id"), r.PathValue("updateID"))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				jsonError(w, "update not found", http.StatusNotFound)
+				return
+			}
+			jsonError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(update)
+	}
+}
+
+func handleGetUpdateEdits(repo *projections.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		edits, err := repo.GetUpdateEdits(r.Context(), r.PathValue("id"), r.PathValue("updateID"))
+		if err != nil {
+			jsonError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(edits)
+	}
+}
+
+func handleGetUpdateComments(repo *projections.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		comments, err := repo.GetUpdateComments(r.Context(), r.PathValue("id"), r.PathValue("updateID"))
+		if err != nil {
+			jsonError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(comments)
+	}
+}
+
+// handleGetEvents lists events from the log, filtered by correlation_id,
+// aggregate_id or type (paged with offset and limit)
+func handleGetEvents(eventStore events.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		var result []*events.Event
+		var err error
+		switch {
+		case query.Get("correlation_id") != "":
+			result, err = eventStore.GetByCorrelationID(r.Context(), query.Get("correlation_id"))
+		case query.Get("aggregate_id") != "":
+			result, err = eventStore.GetByAggregateID(r.Context(), query.Get("aggregate_id"))
+		default:
+			offset, limit, perr := parsePage(query.Get("offset"), query.Get("limit"))
+			if perr != nil {
+				jsonError(w, perr.Error(), http.StatusBadRequest)
+				return
+			}
+			result, err = eventStore.GetAll(r.Context(), query.Get("type"), offset, limit)
+		}
+		if err != nil {
+			jsonError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if result == nil {
+			result = []*events.Event{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// parsePage parses offset and limit query parameters, defaulting to the first 100
+func parsePage(offsetStr, limitStr string) (int, int, error) {
+	offset, limit := 0, 100
+	if offsetStr != "" {
+		n, err := strconv.Atoi(offsetStr)
+		if err != nil || n < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+		offset = n
+	}
+	if limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > 1000 {
+			return 0, 0, errors.New("limit must be between 1 and 1000")
+		}
+		limit = n
+	}
+	return offset, limit, nil
+}
+
+// Admin handlers
+func handleGetDeadLetters(repo *projections.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deadLetters, err := repo.GetDeadLetters(r.Context(), r.URL.Query().Get("status"))
+		if err != nil {
+			jsonError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(deadLetters)
+	}
+}
+
+func handleRetryDeadLetter(projector *projections.Projector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := projector.RetryDeadLetter(r.Context(), r.PathValue("eventID")); err != nil {
+			deadLetterError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"status": "success",
+		})
+	}
+}
+
+func handleSkipDeadLetter(projector *projections.Projector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := projector.SkipDeadLetter(r.Context(), r.PathValue("eventID")); err != nil {
+			deadLetterError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"status": "success",
+		})
+	}
+}
+
+// handleRebuildProjections starts a zero-downtime rebuild of the read models in
+// the background. It runs on the server context so it outlives the request.
+func handleRebuildProjections(ctx context.Context, rebuilder *projections.Rebuilder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := rebuilder.Begin(); err != nil {
+			jsonError(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		go func() {
+			log.Println("Rebuilding projections...")
+			if err := rebuilder.Rebuild(ctx); err != nil {
+				log.Printf("Projection rebuild failed: %v", err)
+			}
+		}()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(rebuilder.Status())
+	}
+}
+
+func handleGetRebuildStatus(rebuilder *projections.Rebuilder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := rebuilder.Status()
+		if status == nil {
+			jsonError(w, "no rebuild has run", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status)
+	}
+}
+
+// deadLetterError maps dead letter errors to HTTP status codes
+func deadLetterError(w http.ResponseWriter, err error) {
+	if errors.Is(err, projections.ErrDeadLetterNotFound) {
+		jsonError(w, "dead letter not found", http.StatusNotFound)
+		return
+	}
+	jsonError(w, err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/status-app/internal/events"
+)
+
+// Headers carrying event metadata between services
+const (
+	headerRequestID     = "X-Request-ID"
+	headerCorrelationID = "X-Correlation-ID"
+	headerCausationID   = "X-Causation-ID"
+	headerActor         = "X-Actor"
+	headerSource        = "X-Source"
+)
+
+const (
+	// defaultActor names the caller when it does not say who it acts for;
+	// the backend has a single API key
+	defaultActor = "api-key"
+
+	// defaultSource names callers that do not identify their service
+	defaultSource = "api"
+)
+
+// withEventMetadata attaches the metadata envelope described by the request
+// headers to the request context, so every event appended while handling the
+// request carries it. Missing IDs default to a fresh request ID, which is
+// echoed back in the X-Request-ID response header.
+func withEventMetadata(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metadata := requestMetadata(r)
+		w.Header().Set(headerRequestID, metadata.RequestID)
+		next.ServeHTTP(w, r.WithContext(events.WithMetadata(r.Context(), metadata)))
+	})
+}
+
+func requestMetadata(r *http.Request) events.Metadata {
+	requestID := headerOr(r, headerRequestID, uuid.New().String())
+	return events.Metadata{
+		CorrelationID: headerOr(r, headerCorrelationID, requestID),
+		CausationID:   headerOr(r, headerCausationID, requestID),
+		Actor:         headerOr(r, headerActor, defaultActor),
+		Source:        headerOr(r, headerSource, defaultSource),
+		RequestID:     requestID,
+	}
+}
+
+func headerOr(r *http.Request, name, fallback string) string {
+	if value := r.Header.Get(name); value != "" {
+		return value
+	}
+	return fallback
+}
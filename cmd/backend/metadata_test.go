@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/status-app/internal/events"
+)
+
+func TestWithEventMetadata(t *testing.T) {
+	t.Run("uses metadata headers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/teams", nil)
+		req.Header.Set(headerRequestID, "req-1")
+		req.Header.Set(headerCorrelationID, "corr-1")
+		req.Header.Set(headerCausationID, "slack-event-1")
+		req.Header.Set(headerActor, "slack:U123")
+		req.Header.Set(headerSource, "slackbot")
+
+		got, rec := serveWithMetadata(req)
+
+		want := events.Metadata{
+			CorrelationID: "corr-1",
+			CausationID:   "slack-event-1",
+			Actor:         "slack:U123",
+			Source:        "slackbot",
+			RequestID:     "req-1",
+		}
+		if got != want {
+			t.Errorf("metadata = %+v, want %+v", got, want)
+		}
+		if id := rec.Header().Get(headerRequestID); id != "req-1" {
+			t.Errorf("X-Request-ID = %q, want %q", id, "req-1")
+		}
+	})
+
+	t.Run("defaults to a fresh request ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/teams", nil)
+
+		got, rec := serveWithMetadata(req)
+
+		if got.RequestID == "" {
+			t.Fatal("expected a generated request ID")
+		}
+		if got.CorrelationID != got.RequestID || got.CausationID != got.RequestID {
+			t.Errorf("correlation and causation IDs should default to the request ID, got %+v", got)
+		}
+		if got.Actor != defaultActor || got.Source != defaultSource {
+			t.Errorf("actor/source = %q/%q, want %q/%q", got.Actor, got.Source, defaultActor, defaultSource)
+		}
+		if id := rec.Header().Get(headerRequestID); id != got.RequestID {
+			t.Errorf("X-Request-ID = %q, want %q", id, got.RequestID)
+		}
+	})
+}
+
+func serveWithMetadata(req *http.Request) (events.Metadata, *httptest.ResponseRecorder) {
+	var got events.Metadata
+	handler := withEventMetadata(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = events.MetadataFromContext(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return got, rec
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -27,6 +28,14 @@ func TestSubmitStatusUpdateRequest_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "content is required",
 		},
+		{
+			name: "sections without content",
+			req: SubmitStatusUpdateRequest{
+				Author:   "John Doe",
+				Sections: &UpdateSectionsRequest{Done: "Fixed the bug", Health: "green"},
+			},
+			wantErr: false,
+		},
 		{
 			name: "content too long",
 			req: SubmitStatusUpdateRequest{
@@ -122,3 +131,123 @@ func TestRegisterTeamRequest_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestEditStatusUpdateRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     EditStatusUpdateRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid request",
+			req:     EditStatusUpdateRequest{Content: "Fixed the typo"},
+			wantErr: false,
+		},
+		{
+			name:    "missing content",
+			req:     EditStatusUpdateRequest{SlackUser: "U123"},
+			wantErr: true,
+			errMsg:  "content is required",
+		},
+		{
+			name:    "content too long",
+			req:     EditStatusUpdateRequest{Content: string(make([]byte, 501))},
+			wantErr: true,
+			errMsg:  "content must be 500 characters or less",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.Error() != tt.errMsg {
+				t.Errorf("Validate() error message = %v, want %v", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestCommentOnStatusUpdateRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     CommentOnStatusUpdateRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid request",
+			req:     CommentOnStatusUpdateRequest{Content: "Does that unblock the release?", Author: "U123"},
+			wantErr: false,
+		},
+		{
+			name:    "missing content",
+			req:     CommentOnStatusUpdateRequest{Author: "U123"},
+			wantErr: true,
+			errMsg:  "content is required",
+		},
+		{
+			name:    "missing author",
+			req:     CommentOnStatusUpdateRequest{Content: "Nice"},
+			wantErr: true,
+			errMsg:  "author is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.Error() != tt.errMsg {
+				t.Errorf("Validate() error message = %v, want %v", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestChangeTeamHealthRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     ChangeTeamHealthRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid request",
+			req:     ChangeTeamHealthRequest{Health: "amber", Reason: "Waiting on the vendor"},
+			wantErr: false,
+		},
+		{
+			name:    "missing health",
+			req:     ChangeTeamHealthRequest{Reason: "Waiting on the vendor"},
+			wantErr: true,
+			errMsg:  "health is required",
+		},
+		{
+			name:    "reason too long",
+			req:     ChangeTeamHealthRequest{Health: "red", Reason: strings.Repeat("a", 501)},
+			wantErr: true,
+			errMsg:  "reason must be 500 characters or less",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.Error() != tt.errMsg {
+				t.Errorf("Validate() error message = %v, want %v", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"os/signal"
 	"syscall"
@@ -23,6 +25,7 @@ type SlackBot struct {
 	cfg       *config.Config
 	client    *http.Client
 	slackAPI  *slack.Client
+	messages  *recordedMessages
 }
 
 func NewSlackBot(cfg *config.Config, slackAPI *slack.Client) *SlackBot {
@@ -32,6 +35,7 @@ func NewSlackBot(cfg *config.Config, slackAPI *slack.Client) *SlackBot {
 			Timeout: 10 * time.Second,
 		},
 		slackAPI: slackAPI,
+		messages: newRecordedMessages(),
 	}
 }
 
@@ -128,8 +132,40 @@ func main() {
 	server.Shutdown(ctx)
 }
 
+// origin identifies the Slack interaction behind a backend request, so events
+// it produces can be traced back to it
+type origin struct {
+	correlationID string // Slack event ID or trigger ID
+	slackUser     string
+
+	// idempotencyKey identifies the Slack message, so redeliveries and retries
+	// of it are only recorded once
+	idempotencyKey string
+}
+
+// setEventMetadata passes the origin to the backend as event metadata headers
+func (o origin) setEventMetadata(req *http.Request) {
+	req.Header.Set("X-Source", "slackbot")
+	if o.correlationID != "" {
+		req.Header.Set("X-Correlation-ID", o.correlationID)
+		req.Header.Set("X-Causation-ID", o.correlationID)
+	}
+	if o.slackUser != "" {
+		req.Header.Set("X-Actor", "slack:"+o.slackUser)
+	}
+}
+
+// slackEventID returns the ID Slack assigned to a callback event
+func slackEventID(event slackevents.EventsAPIEvent) string {
+	if callback, ok := event.Data.(*slackevents.EventsAPICallbackEvent); ok {
+		return callback.EventID
+	}
+	return ""
+}
+
 func (bot *SlackBot) handleEvent(event slackevents.EventsAPIEvent) {
 	ctx := context.Background()
+	eventID := slackEventID(event)
 	
 	switch event.Type {
 	case slackevents.CallbackEvent:
@@ -137,12 +173,20 @@ func (bot *SlackBot) handleEvent(event slackevents.EventsAPIEvent) {
 		switch ev := innerEvent.Data.(type) {
 		case *slackevents.AppMentionEvent:
 			slackMessagesReceivedTotal.WithLabelValues("mention").Inc()
-			log.Printf("Bot mentioned by user %s in channel %s: %s", ev.User, ev.Channel, ev.Text)
+			log.Printf("Bot mentioned by user %s in channel %s (event %s): %s", ev.User, ev.Channel, eventID, ev.Text)
 			
 			channelID := ev.Channel
 			channelName := bot.getChannelName(channelID)
 			
-			if err := bot.sendStatusUpdate(ctx, channelID, channelName, ev.Text, ev.User); err != nil {
+			from := origin{eventID, ev.User, messageIdempotencyKey(ev.Channel, ev.TimeStamp)}
+			if bot.handleThreadReply(ctx, from, channelID, ev.TimeStamp, ev.ThreadTimeStamp, ev.Text, ev.User) {
+				return
+			}
+			if err := bot.sendStatusUpdate(ctx, from, channelID, channelName, ev.TimeStamp, ev.ThreadTimeStamp, ev.Text, ev.User); err != nil {
+				if errors.Is(err, errAlreadySubmitted) {
+					log.Printf("Status update for message %s in %s was already submitted", ev.TimeStamp, channelID)
+					return
+				}
 				slackbotErrorsTotal.WithLabelValues("backend_error").Inc()
 				log.Printf("Failed to send status update: %v", err)
 				bot.sendSlackMessage(ev.Channel, "❌ Failed to record your status update. Please try again.")
@@ -153,19 +197,40 @@ func (bot *SlackBot) handleEvent(event slackevents.EventsAPIEvent) {
 			bot.sendSlackMessage(ev.Channel, "✅ Status update recorded!")
 			
 		case *slackevents.MessageEvent:
-			// Ignore bot messages and message subtypes (edits, deletes, etc)
-			if ev.BotID != "" || ev.SubType != "" {
+			if ev.BotID != "" {
+				return
+			}
+
+			// Edits and deletions apply to the update the message became;
+			// other subtypes (joins, bot messages, etc) are ignored
+			switch ev.SubType {
+			case "", slack.MsgSubTypeThreadBroadcast:
+			case slack.MsgSubTypeMessageChanged:
+				bot.handleMessageChanged(ctx, eventID, ev)
+				return
+			case slack.MsgSubTypeMessageDeleted:
+				bot.handleMessageDeleted(ctx, eventID, ev)
+				return
+			default:
 				return
 			}
 			
 			slackMessagesReceivedTotal.WithLabelValues("direct_message").Inc()
-			log.Printf("Received message from user %s in channel %s: %s", ev.User, ev.Channel, ev.Text)
+			log.Printf("Received message from user %s in channel %s (event %s): %s", ev.User, ev.Channel, eventID, ev.Text)
 			
 			channelID := ev.Channel
 			channelName := bot.getChannelName(channelID)
 			
 			// Send status update to Commands service
-			if err := bot.sendStatusUpdate(ctx, channelID, channelName, ev.Text, ev.User); err != nil {
+			from := origin{eventID, ev.User, messageIdempotencyKey(ev.Channel, ev.TimeStamp)}
+			if bot.handleThreadReply(ctx, from, channelID, ev.TimeStamp, ev.ThreadTimeStamp, ev.Text, ev.User) {
+				return
+			}
+			if err := bot.sendStatusUpdate(ctx, from, channelID, channelName, ev.TimeStamp, ev.ThreadTimeStamp, ev.Text, ev.User); err != nil {
+				if errors.Is(err, errAlreadySubmitted) {
+					log.Printf("Status update for message %s in %s was already submitted", ev.TimeStamp, channelID)
+					return
+				}
 				slackbotErrorsTotal.WithLabelValues("backend_error").Inc()
 				log.Printf("Failed to send status update: %v", err)
 				bot.sendSlackMessage(ev.Channel, "❌ Failed to record your status update. Please try again.")
@@ -178,42 +243,319 @@ func (bot *SlackBot) handleEvent(event slackevents.EventsAPIEvent) {
 	}
 }
 
-func (bot *SlackBot) sendStatusUpdate(ctx context.Context, channelID, channelName, content, author string) error {
-	payload := map[string]string{
+// errAlreadySubmitted reports that another delivery of the same Slack message
+// submitted, or is submitting, its status update; it has been confirmed in
+// Slack already
+var errAlreadySubmitted = errors.New("status update already submitted")
+
+// sendStatusUpdate records the message at ts as a status update linked to
+// that message, remembering the update it became so later edits and deletions
+// can be applied to it
+func (bot *SlackBot) sendStatusUpdate(ctx context.Context, from origin, channelID, channelName, ts, threadTS, content, author string) error {
+	payload := map[string]interface{}{
 		"content":      content,
 		"author":       author,
 		"channel_name": channelName,
 	}
-	
+	if ts != "" {
+		payload["slack_message"] = map[string]string{
+			"channel":   channelID,
+			"ts":        ts,
+			"thread_ts": threadTS,
+			"permalink": bot.getPermalink(channelID, ts),
+		}
+	}
+
+	updateID, err := bot.postStatusUpdate(ctx, from, channelID, payload)
+	if err != nil && !errors.Is(err, errAlreadySubmitted) {
+		return err
+	}
+	bot.messages.Record(channelID, ts, updateID)
+	return err
+}
+
+// postStatusUpdate submits a status update for the team of channelID,
+// returning the ID of the update it became. The ID is empty if the response
+// could not be read; the update is recorded all the same. A request whose
+// idempotency key is still in use, or whose response is replayed, fails with
+// errAlreadySubmitted, along with the update ID if the earlier request finished.
+func (bot *SlackBot) postStatusUpdate(ctx context.Context, from origin, channelID string, payload map[string]interface{}) (string, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return err
+		return "", err
 	}
 	
 	url := bot.cfg.CommandsURL + "/teams/" + channelID + "/updates"
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
 	if err != nil {
-		return err
+		return "", err
 	}
 	
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+bot.cfg.APISecret)
+	from.setEventMetadata(req)
+	if from.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", from.idempotencyKey)
+	}
 	
 	resp, err := bot.client.Do(req)
 	if err != nil {
 		backendAPICallsTotal.WithLabelValues("submit_update", "error").Inc()
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 	
+	// Only the idempotency middleware's own conflict means another delivery
+	// is submitting the message; any other 409 is a failed submission
+	if resp.StatusCode == http.StatusConflict && resp.Header.Get("Idempotent-In-Progress") == "true" {
+		backendAPICallsTotal.WithLabelValues("submit_update", "duplicate").Inc()
+		return "", errAlreadySubmitted
+	}
 	if resp.StatusCode != http.StatusCreated {
 		backendAPICallsTotal.WithLabelValues("submit_update", "error").Inc()
 		log.Printf("Failed to submit update: status %d", resp.StatusCode)
-		return fmt.Errorf("backend returned status %d", resp.StatusCode)
+		return "", fmt.Errorf("backend returned status %d", resp.StatusCode)
 	}
 	
 	backendAPICallsTotal.WithLabelValues("submit_update", "success").Inc()
-	return nil
+
+	var result struct {
+		UpdateID string `json:"update_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		// The update is recorded and linked to its message, so later edits
+		// find it through the backend instead
+		log.Printf("Failed to read submitted update ID: %v", err)
+		return "", nil
+	}
+	if resp.Header.Get("Idempotent-Replayed") == "true" {
+		return result.UpdateID, errAlreadySubmitted
+	}
+	return result.UpdateID, nil
+}
+
+// handleThreadReply records a reply in the thread under a status update as a
+// comment on that update. It reports false for messages that are not replies
+// to a status update, which are recorded as updates of their own.
+func (bot *SlackBot) handleThreadReply(ctx context.Context, from origin, channelID, ts, threadTS, content, author string) bool {
+	if threadTS == "" || threadTS == ts {
+		return false
+	}
+	updateID, ok := bot.findUpdateID(ctx, from, channelID, threadTS)
+	if !ok {
+		return false
+	}
+
+	slackMessagesReceivedTotal.WithLabelValues("thread_reply").Inc()
+	log.Printf("Message %s in channel %s replies to status update %s (event %s)", ts, channelID, updateID, from.correlationID)
+
+	if err := bot.sendComment(ctx, from, channelID, updateID, ts, threadTS, content, author); err != nil {
+		slackbotErrorsTotal.WithLabelValues("backend_error").Inc()
+		log.Printf("Failed to record comment on status update %s: %v", updateID, err)
+		bot.sendSlackMessage(channelID, "❌ Failed to record your reply. Please try again.")
+	}
+	return true
+}
+
+// sendComment records the reply at ts as a comment on updateID. A reply to an
+// update deleted in the meantime is dropped.
+func (bot *SlackBot) sendComment(ctx context.Context, from origin, channelID, updateID, ts, threadTS, content, author string) error {
+	payload := map[string]interface{}{
+		"content":    content,
+		"author":     author,
+		"slack_user": author,
+		"slack_message": map[string]string{
+			"channel":   channelID,
+			"ts":        ts,
+			"thread_ts": threadTS,
+			"permalink": bot.getPermalink(channelID, ts),
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := bot.cfg.CommandsURL + "/teams/" + channelID + "/updates/" + updateID + "/comments"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+bot.cfg.APISecret)
+	from.setEventMetadata(req)
+	if from.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", from.idempotencyKey)
+	}
+
+	resp, err := bot.client.Do(req)
+	if err != nil {
+		backendAPICallsTotal.WithLabelValues("comment_update", "error").Inc()
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated, http.StatusNotFound:
+		backendAPICallsTotal.WithLabelValues("comment_update", "success").Inc()
+		return nil
+	default:
+		backendAPICallsTotal.WithLabelValues("comment_update", "error").Inc()
+		return fmt.Errorf("backend returned status %d", resp.StatusCode)
+	}
+}
+
+// findUpdateID returns the status update the message at ts in channelID
+// became, asking the backend for messages the bot has not seen itself
+func (bot *SlackBot) findUpdateID(ctx context.Context, from origin, channelID, ts string) (string, bool) {
+	if updateID, ok := bot.messages.UpdateID(channelID, ts); ok {
+		return updateID, true
+	}
+
+	url := bot.cfg.CommandsURL + "/teams/" + channelID + "/updates?slack_ts=" + neturl.QueryEscape(ts)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", false
+	}
+	req.Header.Set("Authorization", "Bearer "+bot.cfg.APISecret)
+	from.setEventMetadata(req)
+
+	resp, err := bot.client.Do(req)
+	if err != nil {
+		backendAPICallsTotal.WithLabelValues("find_update", "error").Inc()
+		log.Printf("Failed to look up update for message %s in channel %s: %v", ts, channelID, err)
+		return "", false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		backendAPICallsTotal.WithLabelValues("find_update", "error").Inc()
+		log.Printf("Failed to look up update for message %s in channel %s: status %d", ts, channelID, resp.StatusCode)
+		return "", false
+	}
+	backendAPICallsTotal.WithLabelValues("find_update", "success").Inc()
+
+	var updates []struct {
+		UpdateID string `json:"update_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&updates); err != nil || len(updates) == 0 {
+		return "", false
+	}
+	bot.messages.Record(channelID, ts, updates[0].UpdateID)
+	return updates[0].UpdateID, true
+}
+
+// handleMessageChanged applies an edited Slack message to its status update
+func (bot *SlackBot) handleMessageChanged(ctx context.Context, eventID string, ev *slackevents.MessageEvent) {
+	if ev.Message == nil || ev.Message.BotID != "" {
+		return
+	}
+	// Slack also reports unfurled links and other changes that keep the text
+	if ev.PreviousMessage != nil && ev.PreviousMessage.Text == ev.Message.Text {
+		return
+	}
+
+	from := origin{eventID, ev.Message.User, ""}
+	updateID, ok := bot.findUpdateID(ctx, from, ev.Channel, ev.Message.Timestamp)
+	if !ok {
+		log.Printf("Ignoring edit of unrecorded message %s in channel %s (event %s)", ev.Message.Timestamp, ev.Channel, eventID)
+		return
+	}
+
+	slackMessagesReceivedTotal.WithLabelValues("message_changed").Inc()
+	log.Printf("Message %s in channel %s edited by user %s (event %s)", ev.Message.Timestamp, ev.Channel, ev.Message.User, eventID)
+
+	payload := map[string]string{
+		"content":    ev.Message.Text,
+		"slack_user": ev.Message.User,
+	}
+	if err := bot.sendUpdateChange(ctx, from, http.MethodPatch, ev.Channel, updateID, payload, "edit_update"); err != nil {
+		if errors.Is(err, errChangeRejected) {
+			log.Printf("Edit of status update %s was not applied: %v", updateID, err)
+			return
+		}
+		slackbotErrorsTotal.WithLabelValues("backend_error").Inc()
+		log.Printf("Failed to edit status update %s: %v", updateID, err)
+	}
+}
+
+// handleMessageDeleted deletes the status update a deleted Slack message became
+func (bot *SlackBot) handleMessageDeleted(ctx context.Context, eventID string, ev *slackevents.MessageEvent) {
+	var slackUser string
+	if ev.PreviousMessage != nil {
+		slackUser = ev.PreviousMessage.User
+	}
+
+	from := origin{eventID, slackUser, ""}
+	updateID, ok := bot.findUpdateID(ctx, from, ev.Channel, ev.DeletedTimeStamp)
+	if !ok {
+		return
+	}
+
+	slackMessagesReceivedTotal.WithLabelValues("message_deleted").Inc()
+	log.Printf("Message %s in channel %s deleted (event %s)", ev.DeletedTimeStamp, ev.Channel, eventID)
+
+	payload := map[string]string{"slack_user": slackUser}
+	if err := bot.sendUpdateChange(ctx, from, http.MethodDelete, ev.Channel, updateID, payload, "delete_update"); err != nil {
+		if errors.Is(err, errChangeRejected) {
+			log.Printf("Deletion of status update %s was not applied: %v", updateID, err)
+			return
+		}
+		slackbotErrorsTotal.WithLabelValues("backend_error").Inc()
+		log.Printf("Failed to delete status update %s: %v", updateID, err)
+		return
+	}
+	bot.messages.Forget(ev.Channel, ev.DeletedTimeStamp)
+}
+
+// errChangeRejected reports that the backend refused an edit or deletion,
+// because the update is gone, already has that content or was changed
+// concurrently
+var errChangeRejected = errors.New("backend rejected the change")
+
+// sendUpdateChange sends an edit or deletion of one of a team's updates. A
+// change the backend refuses with 404 or 409 fails with errChangeRejected;
+// Slack may deliver an event more than once, so it is not a backend error.
+func (bot *SlackBot) sendUpdateChange(ctx context.Context, from origin, method, channelID, updateID string, payload map[string]string, call string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := bot.cfg.CommandsURL + "/teams/" + channelID + "/updates/" + updateID
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+bot.cfg.APISecret)
+	from.setEventMetadata(req)
+
+	resp, err := bot.client.Do(req)
+	if err != nil {
+		backendAPICallsTotal.WithLabelValues(call, "error").Inc()
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		backendAPICallsTotal.WithLabelValues(call, "success").Inc()
+		return nil
+	case http.StatusNotFound, http.StatusConflict:
+		backendAPICallsTotal.WithLabelValues(call, "rejected").Inc()
+		var result struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return fmt.Errorf("%w: status %d: %s", errChangeRejected, resp.StatusCode, result.Error)
+	default:
+		backendAPICallsTotal.WithLabelValues(call, "error").Inc()
+		return fmt.Errorf("backend returned status %d", resp.StatusCode)
+	}
 }
 
 func (bot *SlackBot) getChannelName(channelID string) string {
@@ -229,6 +571,22 @@ func (bot *SlackBot) getChannelName(channelID string) string {
 	return info.Name
 }
 
+// getPermalink returns the link to the message at ts in channelID, or "" if
+// Slack cannot provide one
+func (bot *SlackBot) getPermalink(channelID, ts string) string {
+	permalink, err := bot.slackAPI.GetPermalink(&slack.PermalinkParameters{
+		Channel: channelID,
+		Ts:      ts,
+	})
+	if err != nil {
+		slackAPICallsTotal.WithLabelValues("get_permalink", "error").Inc()
+		log.Printf("Failed to get permalink for message %s in channel %s: %v", ts, channelID, err)
+		return ""
+	}
+	slackAPICallsTotal.WithLabelValues("get_permalink", "success").Inc()
+	return permalink
+}
+
 func (bot *SlackBot) sendSlackMessage(channel, message string) {
 	_, _, err := bot.slackAPI.PostMessage(
 		channel,
@@ -253,6 +611,8 @@ func (bot *SlackBot) handleSlashCommand(cmd slack.SlashCommand) {
 		bot.openTeamNameModal(cmd)
 	case "/updates":
 		bot.showTeamUpdates(cmd)
+	case "/status":
+		bot.openStatusUpdateModal(cmd)
 	default:
 		bot.slackAPI.PostEphemeral(
 			cmd.ChannelID,
@@ -267,8 +627,11 @@ func (bot *SlackBot) handleInteractive(callback slack.InteractionCallback) {
 
 	switch callback.Type {
 	case slack.InteractionTypeViewSubmission:
-		if callback.View.CallbackID == "set_team_name" {
+		switch callback.View.CallbackID {
+		case "set_team_name":
 			bot.handleTeamNameSubmission(callback)
+		case statusUpdateCallbackID:
+			bot.handleStatusUpdateSubmission(callback)
 		}
 	}
 }
@@ -320,7 +683,8 @@ func (bot *SlackBot) handleTeamNameSubmission(callback slack.InteractionCallback
 	teamName := callback.View.State.Values["team_name_block"]["team_name_input"].Value
 
 	ctx := context.Background()
-	if err := bot.updateTeamName(ctx, channelID, teamName); err != nil {
+	from := origin{correlationID: callback.TriggerID, slackUser: callback.User.ID}
+	if err := bot.updateTeamName(ctx, from, channelID, teamName); err != nil {
 		log.Printf("Failed to update team name: %v", err)
 		bot.sendSlackMessage(channelID, "❌ Failed to update team name. Please try again.")
 		return
@@ -330,7 +694,7 @@ func (bot *SlackBot) handleTeamNameSubmission(callback slack.InteractionCallback
 	bot.sendSlackMessage(channelID, fmt.Sprintf("✅ Team name updated to '%s'", teamName))
 }
 
-func (bot *SlackBot) updateTeamName(ctx context.Context, channelID, teamName string) error {
+func (bot *SlackBot) updateTeamName(ctx context.Context, from origin, channelID, teamName string) error {
 	payload := map[string]string{
 		"name": teamName,
 	}
@@ -348,6 +712,7 @@ func (bot *SlackBot) updateTeamName(ctx context.Context, channelID, teamName str
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+bot.cfg.APISecret)
+	from.setEventMetadata(req)
 
 	resp, err := bot.client.Do(req)
 	if err != nil {
@@ -377,6 +742,7 @@ return
 }
 
 req.Header.Set("Authorization", "Bearer "+bot.cfg.APISecret)
+origin{correlationID: cmd.TriggerID, slackUser: cmd.UserID}.setEventMetadata(req)
 
 resp, err := bot.client.Do(req)
 if err != nil {
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/status-app/internal/config"
+)
+
+// backendResponding starts a backend that answers every request with status
+// and the given headers
+func backendResponding(t *testing.T, status int, header map[string]string) *SlackBot {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name, value := range header {
+			w.Header().Set(name, value)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{"update_id":"update-1","error":"conflict"}`))
+	}))
+	t.Cleanup(server.Close)
+	return NewSlackBot(&config.Config{CommandsURL: server.URL}, nil)
+}
+
+func TestPostStatusUpdate_OnlyIdempotencyConflictsAreDuplicates(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		header        map[string]string
+		wantDuplicate bool
+		wantErr       bool
+	}{
+		{"created", http.StatusCreated, nil, false, false},
+		{"replayed", http.StatusCreated, map[string]string{"Idempotent-Replayed": "true"}, true, true},
+		{"in progress", http.StatusConflict, map[string]string{"Idempotent-In-Progress": "true"}, true, true},
+		{"command conflict", http.StatusConflict, nil, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := backendResponding(t, tt.status, tt.header)
+			from := origin{idempotencyKey: messageIdempotencyKey("C1", "1700000000.000100")}
+
+			_, err := bot.postStatusUpdate(context.Background(), from, "C1", map[string]interface{}{"content": "Shipped"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("postStatusUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, errAlreadySubmitted) != tt.wantDuplicate {
+				t.Errorf("postStatusUpdate() error = %v, want duplicate %v", err, tt.wantDuplicate)
+			}
+		})
+	}
+}
+
+func TestSendUpdateChange_RejectionsAreNotSuccesses(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusConflict} {
+		bot := backendResponding(t, status, nil)
+		err := bot.sendUpdateChange(context.Background(), origin{}, http.MethodPatch, "C1", "update-1", map[string]string{"content": "Shipped"}, "edit_update")
+		if !errors.Is(err, errChangeRejected) {
+			t.Errorf("status %d: sendUpdateChange() error = %v, want errChangeRejected", status, err)
+		}
+	}
+
+	bot := backendResponding(t, http.StatusOK, nil)
+	if err := bot.sendUpdateChange(context.Background(), origin{}, http.MethodPatch, "C1", "update-1", map[string]string{"content": "Shipped"}, "edit_update"); err != nil {
+		t.Errorf("sendUpdateChange() error = %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"sync"
+)
+
+// maxRecordedMessages bounds how many Slack messages the bot remembers
+const maxRecordedMessages = 10000
+
+// recordedMessages remembers which status update each Slack message became, so
+// edits and deletions of recent messages are applied without asking the
+// backend. The oldest are forgotten once maxRecordedMessages is reached.
+type recordedMessages struct {
+	mu      sync.Mutex
+	updates map[string]string // message key -> update ID
+	order   []string          // message keys, oldest first
+}
+
+func newRecordedMessages() *recordedMessages {
+	return &recordedMessages{updates: make(map[string]string)}
+}
+
+// messageKey identifies a Slack message by its channel and timestamp
+func messageKey(channelID, ts string) string {
+	return channelID + "/" + ts
+}
+
+// messageIdempotencyKey identifies the Slack message at ts in channelID to the
+// backend. An @mention arrives as both an app_mention and a message event, and
+// Slack redelivers events, so the key depends only on the message itself; all
+// of them then record one update. Without a timestamp there is no key.
+func messageIdempotencyKey(channelID, ts string) string {
+	if ts == "" {
+		return ""
+	}
+	return "slack-msg:" + channelID + ":" + ts
+}
+
+// Record remembers that the message at ts in channelID became updateID
+func (m *recordedMessages) Record(channelID, ts, updateID string) {
+	if ts == "" || updateID == "" {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := messageKey(channelID, ts)
+	if _, ok := m.updates[key]; !ok {
+		m.order = append(m.order, key)
+	}
+	m.updates[key] = updateID
+
+	if len(m.order) > maxRecordedMessages {
+		delete(m.updates, m.order[0])
+		m.order = m.order[1:]
+	}
+}
+
+// UpdateID returns the update the message at ts in channelID became
+func (m *recordedMessages) UpdateID(channelID, ts string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updateID, ok := m.updates[messageKey(channelID, ts)]
+	return updateID, ok
+}
+
+// Forget drops a deleted message
+func (m *recordedMessages) Forget(channelID, ts string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := messageKey(channelID, ts)
+	if _, ok := m.updates[key]; !ok {
+		return
+	}
+	delete(m.updates, key)
+	for i, recorded := range m.order {
+		if recorded == key {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+func TestRecordedMessages(t *testing.T) {
+	messages := newRecordedMessages()
+	messages.Record("C1", "1700000000.000100", "update-1")
+
+	if got, ok := messages.UpdateID("C1", "1700000000.000100"); !ok || got != "update-1" {
+		t.Errorf("UpdateID() = %q, %v, want update-1, true", got, ok)
+	}
+	if _, ok := messages.UpdateID("C2", "1700000000.000100"); ok {
+		t.Error("UpdateID() found a message in another channel")
+	}
+
+	messages.Forget("C1", "1700000000.000100")
+	if _, ok := messages.UpdateID("C1", "1700000000.000100"); ok {
+		t.Error("UpdateID() found a forgotten message")
+	}
+}
+
+func TestRecordedMessages_ForgetsOldest(t *testing.T) {
+	messages := newRecordedMessages()
+	for i := 0; i <= maxRecordedMessages; i++ {
+		messages.Record("C1", fmt.Sprint(i), fmt.Sprintf("update-%d", i))
+	}
+
+	if _, ok := messages.UpdateID("C1", "0"); ok {
+		t.Error("expected the oldest message to be forgotten")
+	}
+	if _, ok := messages.UpdateID("C1", fmt.Sprint(maxRecordedMessages)); !ok {
+		t.Error("expected the newest message to be remembered")
+	}
+}
+
+func TestMessageIdempotencyKey_MentionAndMessageShareKey(t *testing.T) {
+	// Slack sends both events, with different event IDs, for one @mention
+	mention := &slackevents.AppMentionEvent{Channel: "C1", TimeStamp: "1700000000.000100", User: "U1"}
+	message := &slackevents.MessageEvent{Channel: "C1", TimeStamp: "1700000000.000100", User: "U1", ClientMsgID: "client-1"}
+
+	mentionKey := messageIdempotencyKey(mention.Channel, mention.TimeStamp)
+	messageKey := messageIdempotencyKey(message.Channel, message.TimeStamp)
+	if mentionKey == "" || mentionKey != messageKey {
+		t.Errorf("keys = %q and %q, want one key for the message", mentionKey, messageKey)
+	}
+
+	if other := messageIdempotencyKey("C2", mention.TimeStamp); other == mentionKey {
+		t.Errorf("message in another channel got the same key %q", other)
+	}
+	if key := messageIdempotencyKey("C1", ""); key != "" {
+		t.Errorf("messageIdempotencyKey() without ts = %q, want no key", key)
+	}
+}
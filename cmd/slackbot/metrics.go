@@ -43,7 +43,7 @@ var (
 			Name:      "commands_handled_total",
 			Help:      "Total number of slash commands handled",
 		},
-		[]string{"command"}, // /set-team-name, /updates
+		[]string{"command"}, // /set-team-name, /updates, /status
 	)
 
 	backendAPICallsTotal = promauto.NewCounterVec(
@@ -53,7 +53,7 @@ var (
 			Name:      "backend_api_calls_total",
 			Help:      "Total number of backend API calls",
 		},
-		[]string{"endpoint", "status"}, // endpoint: submit_update, etc; status: success, error
+		[]string{"endpoint", "status"}, // endpoint: submit_update, etc; status: success, error, duplicate, rejected
 	)
 
 	slackbotErrorsTotal = promauto.NewCounterVec(
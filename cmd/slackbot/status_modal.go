@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/slack-go/slack"
+)
+
+// statusUpdateCallbackID identifies submissions of the structured status update modal
+const statusUpdateCallbackID = "submit_status_update"
+
+// Blocks and actions of the structured status update modal, one per section
+const (
+	doneBlock     = "done_block"
+	doneInput     = "done_input"
+	nextBlock     = "next_block"
+	nextInput     = "next_input"
+	blockersBlock = "blockers_block"
+	blockersInput = "blockers_input"
+	healthBlock   = "health_block"
+	healthInput   = "health_input"
+)
+
+// openStatusUpdateModal opens a form for a structured status update with done,
+// next and blockers sections and a health rating, all optional
+func (bot *SlackBot) openStatusUpdateModal(cmd slack.SlashCommand) {
+	section := func(blockID, actionID, label, placeholder string) *slack.InputBlock {
+		input := slack.NewPlainTextInputBlockElement(
+			slack.NewTextBlockObject(slack.PlainTextType, placeholder, false, false),
+			actionID,
+		).WithMultiline(true)
+		return slack.NewInputBlock(
+			blockID,
+			slack.NewTextBlockObject(slack.PlainTextType, label, false, false),
+			nil,
+			input,
+		).WithOptional(true)
+	}
+
+	health := slack.NewOptionsSelectBlockElement(
+		slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, "How is it going?", false, false),
+		healthInput,
+		slack.NewOptionBlockObject("green", slack.NewTextBlockObject(slack.PlainTextType, "🟢 Green", true, false), nil),
+		slack.NewOptionBlockObject("amber", slack.NewTextBlockObject(slack.PlainTextType, "🟠 Amber", true, false), nil),
+		slack.NewOptionBlockObject("red", slack.NewTextBlockObject(slack.PlainTextType, "🔴 Red", true, false), nil),
+	)
+
+	modalRequest := slack.ModalViewRequest{
+		Type:   slack.VTModal,
+		Title:  slack.NewTextBlockObject(slack.PlainTextType, "Status Update", false, false),
+		Close:  slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		Submit: slack.NewTextBlockObject(slack.PlainTextType, "Post", false, false),
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				section(doneBlock, doneInput, "Done", "What did the team get done?"),
+				section(nextBlock, nextInput, "Next", "What is the team doing next?"),
+				section(blockersBlock, blockersInput, "Blockers", "What is in the way?"),
+				slack.NewInputBlock(
+					healthBlock,
+					slack.NewTextBlockObject(slack.PlainTextType, "Health", false, false),
+					nil,
+					health,
+				).WithOptional(true),
+			},
+		},
+		CallbackID:      statusUpdateCallbackID,
+		PrivateMetadata: cmd.ChannelID,
+	}
+
+	if _, err := bot.slackAPI.OpenView(cmd.TriggerID, modalRequest); err != nil {
+		slackAPICallsTotal.WithLabelValues("open_view", "error").Inc()
+		log.Printf("Failed to open status update modal: %v", err)
+		return
+	}
+	slackAPICallsTotal.WithLabelValues("open_view", "success").Inc()
+}
+
+// statusUpdateSections reads the sections of a submitted status update modal,
+// leaving out the empty ones
+func statusUpdateSections(state *slack.ViewState) map[string]string {
+	sections := make(map[string]string)
+	if state == nil {
+		return sections
+	}
+	for name, field := range map[string][2]string{
+		"done":     {doneBlock, doneInput},
+		"next":     {nextBlock, nextInput},
+		"blockers": {blockersBlock, blockersInput},
+	} {
+		if value := state.Values[field[0]][field[1]].Value; value != "" {
+			sections[name] = value
+		}
+	}
+	if health := state.Values[healthBlock][healthInput].SelectedOption.Value; health != "" {
+		sections["health"] = health
+	}
+	return sections
+}
+
+// handleStatusUpdateSubmission posts a structured status update from the modal
+func (bot *SlackBot) handleStatusUpdateSubmission(callback slack.InteractionCallback) {
+	channelID := callback.View.PrivateMetadata
+	sections := statusUpdateSections(callback.View.State)
+	if sections["done"] == "" && sections["next"] == "" && sections["blockers"] == "" {
+		bot.slackAPI.PostEphemeral(channelID, callback.User.ID,
+			slack.MsgOptionText("❌ Fill in at least one of Done, Next or Blockers to post a status update.", false))
+		return
+	}
+
+	slackMessagesReceivedTotal.WithLabelValues("status_modal").Inc()
+	payload := map[string]interface{}{
+		"author":       callback.User.ID,
+		"channel_name": bot.getChannelName(channelID),
+		"sections":     sections,
+	}
+
+	ctx := context.Background()
+	from := origin{correlationID: callback.TriggerID, slackUser: callback.User.ID, idempotencyKey: "slack-view:" + callback.View.ID}
+	if _, err := bot.postStatusUpdate(ctx, from, channelID, payload); err != nil {
+		if errors.Is(err, errAlreadySubmitted) {
+			return
+		}
+		slackbotErrorsTotal.WithLabelValues("backend_error").Inc()
+		log.Printf("Failed to submit structured status update: %v", err)
+		bot.sendSlackMessage(channelID, "❌ Failed to record your status update. Please try again.")
+		return
+	}
+
+	log.Printf("Successfully submitted structured status update for team %s", channelID)
+	bot.sendSlackMessage(channelID, "✅ Status update recorded!")
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestStatusUpdateSections(t *testing.T) {
+	state := &slack.ViewState{Values: map[string]map[string]slack.BlockAction{
+		doneBlock:     {doneInput: {Value: "Shipped the importer"}},
+		nextBlock:     {nextInput: {Value: ""}},
+		blockersBlock: {blockersInput: {Value: "Waiting on API keys"}},
+		healthBlock:   {healthInput: {SelectedOption: slack.OptionBlockObject{Value: "amber"}}},
+	}}
+
+	want := map[string]string{
+		"done":     "Shipped the importer",
+		"blockers": "Waiting on API keys",
+		"health":   "amber",
+	}
+	if got := statusUpdateSections(state); !reflect.DeepEqual(got, want) {
+		t.Errorf("statusUpdateSections() = %v, want %v", got, want)
+	}
+
+	if got := statusUpdateSections(nil); len(got) != 0 {
+		t.Errorf("statusUpdateSections(nil) = %v, want no sections", got)
+	}
+}
@@ -18,13 +18,21 @@ type SubmitStatusUpdate struct {
 	Author      domain.Author
 	SlackUser   domain.SlackUserID
 	Timestamp   time.Time
+
+	// SlackMessage is the Slack message the update was posted as; zero for
+	// updates submitted through the API
+	SlackMessage domain.SlackMessage
+
+	// Sections makes the update structured, with Content rendered from them;
+	// zero for free-text updates
+	Sections domain.UpdateSections
 }
 
 func (c SubmitStatusUpdate) Validate() error {
 	if c.TeamID.IsEmpty() {
 		return errors.New("team_id is required")
 	}
-	if c.Content.String() == "" {
+	if c.Content.String() == "" && c.Sections.IsZero() {
 		return errors.New("content is required")
 	}
 	if c.Author.String() == "" {
@@ -72,3 +80,94 @@ func (c UpdateTeam) Validate() error {
 	}
 	return nil
 }
+
+// ChangeTeamHealth rates how a team's work is going. Reason is optional;
+// ChangedBy is set when the change came from Slack.
+type ChangeTeamHealth struct {
+	TeamID    domain.TeamID
+	Health    domain.Health
+	Reason    string
+	ChangedBy domain.SlackUserID
+	Timestamp time.Time
+}
+
+func (c ChangeTeamHealth) Validate() error {
+	if c.TeamID.IsEmpty() {
+		return errors.New("team_id is required")
+	}
+	if c.Health.IsZero() {
+		return errors.New("health is required")
+	}
+	return nil
+}
+
+// EditStatusUpdate replaces the content of one of a team's updates. EditedBy
+// is optional; it is set when the edit came from Slack.
+type EditStatusUpdate struct {
+	TeamID    domain.TeamID
+	UpdateID  domain.UpdateID
+	Content   domain.UpdateContent
+	EditedBy  domain.SlackUserID
+	Timestamp time.Time
+}
+
+func (c EditStatusUpdate) Validate() error {
+	if c.TeamID.IsEmpty() {
+		return errors.New("team_id is required")
+	}
+	if c.UpdateID.String() == "" {
+		return errors.New("update_id is required")
+	}
+	if c.Content.String() == "" {
+		return errors.New("content is required")
+	}
+	return nil
+}
+
+// DeleteStatusUpdate removes one of a team's updates. DeletedBy is optional;
+// it is set when the deletion came from Slack.
+type DeleteStatusUpdate struct {
+	TeamID    domain.TeamID
+	UpdateID  domain.UpdateID
+	DeletedBy domain.SlackUserID
+	Timestamp time.Time
+}
+
+func (c DeleteStatusUpdate) Validate() error {
+	if c.TeamID.IsEmpty() {
+		return errors.New("team_id is required")
+	}
+	if c.UpdateID.String() == "" {
+		return errors.New("update_id is required")
+	}
+	return nil
+}
+
+// CommentOnStatusUpdate adds a comment to one of a team's updates. SlackUser
+// and SlackMessage are set when the comment is a reply in the update's Slack
+// thread.
+type CommentOnStatusUpdate struct {
+	TeamID       domain.TeamID
+	UpdateID     domain.UpdateID
+	Content      domain.UpdateContent
+	Author       domain.Author
+	SlackUser    domain.SlackUserID
+	SlackMessage domain.SlackMessage
+	Timestamp    time.Time
+}
+
+func (c CommentOnStatusUpdate) Validate() error {
+	if c.TeamID.IsEmpty() {
+		return errors.New("team_id is required")
+	}
+	if c.UpdateID.String() == "" {
+		return errors.New("update_id is required")
+	}
+	if c.Content.String() == "" {
+		return errors.New("content is required")
+	}
+	if c.Author.String() == "" {
+		return errors.New("author is required")
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ func TestSubmitStatusUpdate_Validate(t *testing.T) {
 	validContent, _ := domain.NewUpdateContent("Working on feature X")
 	validAuthor, _ := domain.NewAuthor("Alice")
 	validSlackUser, _ := domain.NewSlackUserID("alice")
+	validSections, _ := domain.NewUpdateSections("Shipped login", "Start billing", "", domain.HealthGreen)
 
 	tests := []struct {
 		name    string
@@ -42,6 +43,17 @@ func TestSubmitStatusUpdate_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "team_id is required",
 		},
+		{
+			name: "structured command without content",
+			cmd: SubmitStatusUpdate{
+				TeamID:      validTeamID,
+				ChannelName: "engineering",
+				Sections:    validSections,
+				Author:      validAuthor,
+				SlackUser:   validSlackUser,
+			},
+			wantErr: false,
+		},
 		{
 			name: "missing content",
 			cmd: SubmitStatusUpdate{
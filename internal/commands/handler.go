@@ -2,31 +2,95 @@ package commands
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/yourusername/status-app/internal/domain"
 	"github.com/yourusername/status-app/internal/events"
 )
 
+// maxAttempts bounds how often a command is retried after a concurrency conflict
+const maxAttempts = 3
+
 // Handler processes commands and emits events
 type Handler struct {
 	eventStore events.Store
+	teams      *TeamRepository
+	updates    *UpdateRepository
 }
 
 func NewHandler(eventStore events.Store) *Handler {
+	return NewHandlerWithSnapshots(eventStore, nil)
+}
+
+// NewHandlerWithSnapshots returns a Handler that rehydrates aggregates from
+// snapshots kept in snapshots
+func NewHandlerWithSnapshots(eventStore events.Store, snapshots events.SnapshotStore) *Handler {
 	return &Handler{
 		eventStore: eventStore,
+		teams:      NewTeamRepository(eventStore, snapshots),
+		updates:    NewUpdateRepository(eventStore),
 	}
 }
 
-func (h *Handler) Handle(ctx context.Context, cmd Command) error {
+// newEvent marshals data into an event for aggregateID, stamped with the
+// metadata carried by ctx and the current schema version of eventType. A
+// non-zero version asks AppendBatch to store the event at exactly that version.
+func newEvent(ctx context.Context, upcasters *events.Upcasters, eventType string, aggregateID string, version int, data interface{}) (*events.Event, error) {
+	dataJSON, err := events.Encode(eventType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := events.MetadataFromContext(ctx).Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return &events.Event{
+		ID:          uuid.New().String(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Data:        dataJSON,
+		Timestamp:   time.Now(),
+		Metadata:    metadata,
+		Version:     version,
+
+		SchemaVersion: upcasters.CurrentVersion(eventType),
+	}, nil
+}
+
+// Handle validates and applies cmd, returning the IDs it produced and the
+// events it appended
+func (h *Handler) Handle(ctx context.Context, cmd Command) (Result, error) {
 	// Validate command
 	if err := cmd.Validate(); err != nil {
-		return fmt.Errorf("invalid command: %w", err)
+		return Result{}, fmt.Errorf("invalid command: %w", err)
+	}
+
+	// Retry commands that lost an optimistic concurrency race; each attempt
+	// reloads the aggregate so the retry is decided against fresh state
+	var result Result
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		result, err = h.dispatch(ctx, cmd)
+		if !errors.Is(err, events.ErrConcurrencyConflict) {
+			break
+		}
+	}
+
+	if err != nil {
+		log.Printf("command %T failed [%s]: %v", cmd, events.MetadataFromContext(ctx), err)
+		return Result{}, err
 	}
+	log.Printf("command %T handled at position %d [%s]", cmd, result.Position(), events.MetadataFromContext(ctx))
+	return result, nil
+}
 
+func (h *Handler) dispatch(ctx context.Context, cmd Command) (Result, error) {
 	switch c := cmd.(type) {
 	case SubmitStatusUpdate:
 		return h.handleSubmitStatusUpdate(ctx, c)
@@ -34,93 +98,187 @@ func (h *Handler) Handle(ctx context.Context, cmd Command) error {
 		return h.handleRegisterTeam(ctx, c)
 	case UpdateTeam:
 		return h.handleUpdateTeam(ctx, c)
+	case ChangeTeamHealth:
+		return h.handleChangeTeamHealth(ctx, c)
+	case EditStatusUpdate:
+		return h.handleEditStatusUpdate(ctx, c)
+	case DeleteStatusUpdate:
+		return h.handleDeleteStatusUpdate(ctx, c)
+	case CommentOnStatusUpdate:
+		return h.handleCommentOnStatusUpdate(ctx, c)
 	default:
-		return fmt.Errorf("unknown command type: %T", cmd)
+		return Result{}, fmt.Errorf("unknown command type: %T", cmd)
 	}
 }
 
-// createAndAppendEvent is a helper that marshals data and creates an event
-func (h *Handler) createAndAppendEvent(
-	ctx context.Context,
-	eventType string,
-	aggregateID string,
-	data interface{},
-) error {
-	dataJSON, err := json.Marshal(data)
+func (h *Handler) handleSubmitStatusUpdate(ctx context.Context, cmd SubmitStatusUpdate) (Result, error) {
+	exists, err := h.teams.Exists(ctx, cmd.TeamID)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event data: %w", err)
-	}
-
-	event := &events.Event{
-		ID:          uuid.New().String(),
-		Type:        eventType,
-		AggregateID: aggregateID,
-		Data:        dataJSON,
-		Timestamp:   time.Now(),
-		Version:     1,
+		return Result{}, fmt.Errorf("failed to check for existing team: %w", err)
 	}
 
-	return h.eventStore.Append(ctx, event)
-}
-
-func (h *Handler) handleSubmitStatusUpdate(ctx context.Context, cmd SubmitStatusUpdate) error {
-	teamIDStr := cmd.TeamID.String()
-	
-	existingEvents, err := h.eventStore.GetByAggregateID(ctx, teamIDStr)
-	if err != nil {
-		return fmt.Errorf("failed to check for existing team: %w", err)
-	}
+	// Registering an unknown team and submitting its first update are one
+	// atomic batch, so the team never exists without the update. If another
+	// command registers the team first, the batch conflicts and is retried.
+	var batch []*events.Event
 
-	if len(existingEvents) == 0 {
+	if !exists {
 		if cmd.ChannelName == "" {
-			return fmt.Errorf(
+			return Result{}, fmt.Errorf(
 				"expected ChannelName to exist for team auto-registration, but it was empty. "+
 					"TeamID: %s. Cannot auto-register team without channel name",
-				teamIDStr,
+				cmd.TeamID,
 			)
 		}
 
-		registerData := events.TeamRegisteredData{
-			TeamID:       teamIDStr,
-			Name:         cmd.ChannelName,
-			SlackChannel: teamIDStr,
+		name, err := domain.NewTeamName(cmd.ChannelName)
+		if err != nil {
+			return Result{}, fmt.Errorf("invalid channel name for team auto-registration: %w", err)
+		}
+		channel, err := domain.NewSlackChannel(cmd.TeamID.String())
+		if err != nil {
+			return Result{}, err
 		}
 
-		if err := h.createAndAppendEvent(ctx, events.TeamRegistered, teamIDStr, registerData); err != nil {
-			return fmt.Errorf("failed to auto-register team: %w", err)
+		team, err := domain.NewTeam(cmd.TeamID, name, channel)
+		if err != nil {
+			return Result{}, err
+		}
+		registered, err := h.teams.pending(ctx, team)
+		if err != nil {
+			return Result{}, err
 		}
+		batch = append(batch, registered...)
+	}
+
+	updateID, err := domain.NewUpdateID(uuid.New().String())
+	if err != nil {
+		return Result{}, err
+	}
+	var update *domain.Update
+	if cmd.Sections.IsZero() {
+		update, err = domain.NewUpdate(updateID, cmd.TeamID, cmd.Content, cmd.Author, cmd.SlackUser, cmd.SlackMessage, cmd.Timestamp)
+	} else {
+		update, err = domain.NewStructuredUpdate(updateID, cmd.TeamID, cmd.Sections, cmd.Author, cmd.SlackUser, cmd.SlackMessage, cmd.Timestamp)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	submitted, err := h.updates.pending(ctx, update)
+	if err != nil {
+		return Result{}, err
+	}
+	batch = append(batch, submitted...)
+
+	if err := h.eventStore.AppendBatch(ctx, batch...); err != nil {
+		return Result{}, fmt.Errorf("failed to submit status update: %w", err)
+	}
+	return Result{TeamID: cmd.TeamID, UpdateID: updateID, Events: appendedEvents(batch)}, nil
+}
+
+func (h *Handler) handleRegisterTeam(ctx context.Context, cmd RegisterTeam) (Result, error) {
+	teamID, err := domain.NewTeamID(uuid.New().String())
+	if err != nil {
+		return Result{}, err
+	}
+
+	team, err := domain.NewTeam(teamID, cmd.Name, cmd.SlackChannel)
+	if err != nil {
+		return Result{}, err
+	}
+
+	stored, err := h.teams.Save(ctx, team)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{TeamID: teamID, Events: appendedEvents(stored)}, nil
+}
+
+func (h *Handler) handleUpdateTeam(ctx context.Context, cmd UpdateTeam) (Result, error) {
+	team, err := h.teams.Load(ctx, cmd.TeamID)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if err := team.Update(cmd.Name, cmd.SlackChannel); err != nil {
+		return Result{}, err
+	}
+
+	stored, err := h.teams.Save(ctx, team)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{TeamID: cmd.TeamID, Events: appendedEvents(stored)}, nil
+}
+
+func (h *Handler) handleChangeTeamHealth(ctx context.Context, cmd ChangeTeamHealth) (Result, error) {
+	team, err := h.teams.Load(ctx, cmd.TeamID)
+	if err != nil {
+		return Result{}, err
 	}
 
-	data := events.StatusUpdateSubmittedData{
-		UpdateID:  uuid.New().String(),
-		TeamID:    teamIDStr,
-		Content:   cmd.Content.String(),
-		Author:    cmd.Author.String(),
-		SlackUser: cmd.SlackUser.String(),
-		Timestamp: cmd.Timestamp,
+	if err := team.ChangeHealth(cmd.Health, cmd.Reason, cmd.ChangedBy, cmd.Timestamp); err != nil {
+		return Result{}, err
 	}
 
-	return h.createAndAppendEvent(ctx, events.StatusUpdateSubmitted, teamIDStr, data)
+	stored, err := h.teams.Save(ctx, team)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{TeamID: cmd.TeamID, Events: appendedEvents(stored)}, nil
 }
 
-func (h *Handler) handleRegisterTeam(ctx context.Context, cmd RegisterTeam) error {
-	teamID := uuid.New().String()
+func (h *Handler) handleEditStatusUpdate(ctx context.Context, cmd EditStatusUpdate) (Result, error) {
+	update, err := h.updates.Load(ctx, cmd.UpdateID)
+	if err != nil {
+		return Result{}, err
+	}
 
-	data := events.TeamRegisteredData{
-		TeamID:       teamID,
-		Name:         cmd.Name.String(),
-		SlackChannel: cmd.SlackChannel.String(),
+	if err := update.Edit(cmd.TeamID, cmd.Content, cmd.EditedBy, cmd.Timestamp); err != nil {
+		return Result{}, err
 	}
 
-	return h.createAndAppendEvent(ctx, events.TeamRegistered, teamID, data)
+	stored, err := h.updates.Save(ctx, update)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{TeamID: cmd.TeamID, UpdateID: cmd.UpdateID, Events: appendedEvents(stored)}, nil
 }
 
-func (h *Handler) handleUpdateTeam(ctx context.Context, cmd UpdateTeam) error {
-	data := events.TeamUpdatedData{
-		TeamID:       cmd.TeamID.String(),
-		Name:         cmd.Name.String(),
-		SlackChannel: cmd.SlackChannel.String(),
+func (h *Handler) handleDeleteStatusUpdate(ctx context.Context, cmd DeleteStatusUpdate) (Result, error) {
+	update, err := h.updates.Load(ctx, cmd.UpdateID)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if err := update.Delete(cmd.TeamID, cmd.DeletedBy, cmd.Timestamp); err != nil {
+		return Result{}, err
 	}
 
-	return h.createAndAppendEvent(ctx, events.TeamUpdated, cmd.TeamID.String(), data)
+	stored, err := h.updates.Save(ctx, update)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{TeamID: cmd.TeamID, UpdateID: cmd.UpdateID, Events: appendedEvents(stored)}, nil
+}
+
+func (h *Handler) handleCommentOnStatusUpdate(ctx context.Context, cmd CommentOnStatusUpdate) (Result, error) {
+	update, err := h.updates.Load(ctx, cmd.UpdateID)
+	if err != nil {
+		return Result{}, err
+	}
+
+	commentID, err := domain.NewCommentID(uuid.New().String())
+	if err != nil {
+		return Result{}, err
+	}
+	if err := update.Comment(cmd.TeamID, commentID, cmd.Content, cmd.Author, cmd.SlackUser, cmd.SlackMessage, cmd.Timestamp); err != nil {
+		return Result{}, err
+	}
+
+	stored, err := h.updates.Save(ctx, update)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{TeamID: cmd.TeamID, UpdateID: cmd.UpdateID, CommentID: commentID, Events: appendedEvents(stored)}, nil
 }
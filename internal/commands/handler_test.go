@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -9,49 +10,51 @@ import (
 	"github.com/yourusername/status-app/internal/events"
 )
 
-// MockEventStore for testing
-type MockEventStore struct {
-	events []*events.Event
-	err    error
+// conflictingStore is the in-memory event store with its next appends
+// reporting concurrency conflicts
+type conflictingStore struct {
+	*events.MemoryStore
+	conflicts int // number of upcoming appends that report a conflict
 }
 
-func (m *MockEventStore) Append(ctx context.Context, event *events.Event) error {
-	if m.err != nil {
-		return m.err
+func (s *conflictingStore) conflict() bool {
+	if s.conflicts == 0 {
+		return false
 	}
-	m.events = append(m.events, event)
-	return nil
+	s.conflicts--
+	return true
 }
 
-func (m *MockEventStore) GetByAggregateID(ctx context.Context, aggregateID string) ([]*events.Event, error) {
-	if m.err != nil {
-		return nil, m.err
-	}
-	var filtered []*events.Event
-	for _, e := range m.events {
-		if e.AggregateID == aggregateID {
-			filtered = append(filtered, e)
-		}
-	}
-	return filtered, nil
+func (s *conflictingStore) Append(ctx context.Context, event *events.Event) error {
+	return s.AppendExpected(ctx, events.AnyVersion, event)
 }
 
-func (m *MockEventStore) GetAll(ctx context.Context, eventType string, offset, limit int) ([]*events.Event, error) {
-	return m.events, m.err
+func (s *conflictingStore) AppendExpected(ctx context.Context, expectedVersion int, event *events.Event) error {
+	if s.conflict() {
+		return events.ErrConcurrencyConflict
+	}
+	return s.MemoryStore.AppendExpected(ctx, expectedVersion, event)
 }
 
-func (m *MockEventStore) Subscribe(ctx context.Context, eventTypes []string) (<-chan *events.Event, error) {
-	ch := make(chan *events.Event)
-	close(ch)
-	return ch, nil
+func (s *conflictingStore) AppendBatch(ctx context.Context, batch ...*events.Event) error {
+	if s.conflict() {
+		return events.ErrConcurrencyConflict
+	}
+	return s.MemoryStore.AppendBatch(ctx, batch...)
 }
 
-func (m *MockEventStore) Close() error {
-	return nil
+// storedEvents returns the whole log of store
+func storedEvents(t *testing.T, store events.Store) []*events.Event {
+	t.Helper()
+	stored, err := store.ReadFrom(context.Background(), 0, 1000)
+	if err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+	return stored
 }
 
 func TestHandler_HandleSubmitStatusUpdate(t *testing.T) {
-	store := &MockEventStore{}
+	store := events.NewMemoryStore()
 	handler := NewHandler(store)
 
 	teamID, _ := domain.NewTeamID("team-1")
@@ -68,30 +71,95 @@ func TestHandler_HandleSubmitStatusUpdate(t *testing.T) {
 		Timestamp:   time.Now(),
 	}
 
-	err := handler.Handle(context.Background(), cmd)
+	result, err := handler.Handle(context.Background(), cmd)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
 
-	if len(store.events) != 2 {
-		t.Fatalf("expected 2 events (auto-register + status update), got %d", len(store.events))
+	stored := storedEvents(t, store)
+	if len(stored) != 2 {
+		t.Fatalf("expected 2 events (auto-register + status update), got %d", len(stored))
+	}
+
+	if stored[0].Type != "team.registered" {
+		t.Errorf("expected first event type team.registered, got %s", stored[0].Type)
+	}
+
+	if stored[1].Type != "status_update.submitted" {
+		t.Errorf("expected second event type status_update.submitted, got %s", stored[1].Type)
+	}
+
+	if stored[0].AggregateID != "team-1" {
+		t.Errorf("expected team aggregate ID team-1, got %s", stored[0].AggregateID)
+	}
+
+	// The update is its own aggregate, keyed by its update ID
+	data, err := events.Decode[events.StatusUpdateSubmittedData](stored[1])
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if stored[1].AggregateID != data.UpdateID {
+		t.Errorf("expected aggregate ID %s, got %s", data.UpdateID, stored[1].AggregateID)
+	}
+	if data.TeamID != "team-1" {
+		t.Errorf("expected team ID team-1, got %s", data.TeamID)
+	}
+
+	// The result names the created update and where both events landed
+	if result.TeamID.String() != "team-1" || result.UpdateID.String() != data.UpdateID {
+		t.Errorf("expected result for team-1 update %s, got %+v", data.UpdateID, result)
+	}
+	if len(result.Events) != 2 || result.Events[1].ID != stored[1].ID {
+		t.Fatalf("expected the 2 appended events in the result, got %+v", result.Events)
 	}
+	if result.Position() != stored[1].Position {
+		t.Errorf("expected position %d, got %d", stored[1].Position, result.Position())
+	}
+}
 
-	if store.events[0].Type != "team.registered" {
-		t.Errorf("expected first event type team.registered, got %s", store.events[0].Type)
+func TestHandler_HandleSubmitStatusUpdate_LinksSlackMessage(t *testing.T) {
+	store := events.NewMemoryStore()
+	handler := NewHandler(store)
+
+	teamID, _ := domain.NewTeamID("C123")
+	content, _ := domain.NewUpdateContent("Fixed critical bug")
+	author, _ := domain.NewAuthor("U123")
+	slackUser, _ := domain.NewSlackUserID("U123")
+	message, _ := domain.NewSlackMessage("C123", "1700000000.000200", "1700000000.000100", "https://example.slack.com/archives/C123/p1700000000000200")
+
+	result, err := handler.Handle(context.Background(), SubmitStatusUpdate{
+		TeamID:       teamID,
+		ChannelName:  "engineering",
+		Content:      content,
+		Author:       author,
+		SlackUser:    slackUser,
+		SlackMessage: message,
+		Timestamp:    time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
 	}
 
-	if store.events[1].Type != "status_update.submitted" {
-		t.Errorf("expected second event type status_update.submitted, got %s", store.events[1].Type)
+	stored := storedEvents(t, store)
+	data, err := events.Decode[events.StatusUpdateSubmittedData](stored[1])
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if data.SlackChannel != "C123" || data.SlackTS != message.TS() || data.SlackThreadTS != message.ThreadTS() || data.SlackPermalink != message.Permalink() {
+		t.Errorf("expected the Slack message in the event, got %+v", data)
 	}
 
-	if store.events[1].AggregateID != "team-1" {
-		t.Errorf("expected aggregate ID team-1, got %s", store.events[1].AggregateID)
+	update, err := handler.updates.Load(context.Background(), result.UpdateID)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if update.SlackMessage() != message {
+		t.Errorf("expected rehydrated message %+v, got %+v", message, update.SlackMessage())
 	}
 }
 
 func TestHandler_HandleRegisterTeam(t *testing.T) {
-	store := &MockEventStore{}
+	store := events.NewMemoryStore()
 	handler := NewHandler(store)
 
 	name, _ := domain.NewTeamName("Engineering")
@@ -102,24 +170,34 @@ func TestHandler_HandleRegisterTeam(t *testing.T) {
 		SlackChannel: channel,
 	}
 
-	err := handler.Handle(context.Background(), cmd)
+	result, err := handler.Handle(context.Background(), cmd)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
 
-	if len(store.events) != 1 {
-		t.Fatalf("expected 1 event, got %d", len(store.events))
+	stored := storedEvents(t, store)
+	if len(stored) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(stored))
 	}
 
-	event := store.events[0]
+	event := stored[0]
 	if event.Type != "team.registered" {
 		t.Errorf("expected event type team.registered, got %s", event.Type)
 	}
+
+	// The generated team ID is returned, along with the event's position
+	if result.TeamID.String() != event.AggregateID {
+		t.Errorf("expected team ID %s, got %s", event.AggregateID, result.TeamID)
+	}
+	if result.Position() != event.Position || result.Events[0].Version != 1 {
+		t.Errorf("expected version 1 at position %d, got %+v", event.Position, result.Events)
+	}
 }
 
 func TestHandler_HandleUpdateTeam(t *testing.T) {
-	store := &MockEventStore{}
+	store := events.NewMemoryStore()
 	handler := NewHandler(store)
+	seedTeam(t, store, "team-1", "Engineering", "#engineering")
 
 	teamID, _ := domain.NewTeamID("team-1")
 	name, _ := domain.NewTeamName("Updated Engineering")
@@ -131,16 +209,17 @@ func TestHandler_HandleUpdateTeam(t *testing.T) {
 		SlackChannel: channel,
 	}
 
-	err := handler.Handle(context.Background(), cmd)
+	_, err := handler.Handle(context.Background(), cmd)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
 
-	if len(store.events) != 1 {
-		t.Fatalf("expected 1 event, got %d", len(store.events))
+	stored := storedEvents(t, store)
+	if len(stored) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(stored))
 	}
 
-	event := store.events[0]
+	event := stored[1]
 	if event.Type != "team.updated" {
 		t.Errorf("expected event type team.updated, got %s", event.Type)
 	}
@@ -148,10 +227,294 @@ func TestHandler_HandleUpdateTeam(t *testing.T) {
 	if event.AggregateID != "team-1" {
 		t.Errorf("expected aggregate ID team-1, got %s", event.AggregateID)
 	}
+
+	if event.Version != 2 {
+		t.Errorf("expected version 2, got %d", event.Version)
+	}
+}
+
+func TestHandler_HandleUpdateTeam_EnforcesInvariants(t *testing.T) {
+	tests := []struct {
+		name    string
+		seed    bool
+		newName string
+		channel string
+		wantErr error
+	}{
+		{"unknown team", false, "Product", "#product", domain.ErrTeamNotFound},
+		{"unchanged team", true, "Engineering", "#engineering", domain.ErrTeamUnchanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := events.NewMemoryStore()
+			handler := NewHandler(store)
+			if tt.seed {
+				seedTeam(t, store, "team-1", "Engineering", "#engineering")
+			}
+			seeded := len(storedEvents(t, store))
+
+			cmd := UpdateTeam{
+				TeamID:       mustTeamID(t, "team-1"),
+				Name:         mustTeamName(t, tt.newName),
+				SlackChannel: mustChannel(t, tt.channel),
+			}
+
+			_, err := handler.Handle(context.Background(), cmd)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got: %v", tt.wantErr, err)
+			}
+			if stored := storedEvents(t, store); len(stored) != seeded {
+				t.Errorf("expected no new events, got %d", len(stored)-seeded)
+			}
+		})
+	}
+}
+
+func TestHandler_RehydratesTeam(t *testing.T) {
+	store := events.NewMemoryStore()
+	handler := NewHandler(store)
+	seedTeam(t, store, "team-1", "Engineering", "#engineering")
+
+	rename := UpdateTeam{
+		TeamID:       mustTeamID(t, "team-1"),
+		Name:         mustTeamName(t, "Product"),
+		SlackChannel: mustChannel(t, "#engineering"),
+	}
+	if _, err := handler.Handle(context.Background(), rename); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// Renaming again to the same name is a no-op the domain rejects, which
+	// it can only know from the team.updated event just stored
+	if _, err := handler.Handle(context.Background(), rename); !errors.Is(err, domain.ErrTeamUnchanged) {
+		t.Fatalf("expected ErrTeamUnchanged, got: %v", err)
+	}
+
+	team, err := NewTeamRepository(store, nil).Load(context.Background(), mustTeamID(t, "team-1"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if team.Name().String() != "Product" {
+		t.Errorf("expected name Product, got %s", team.Name())
+	}
+	if team.Version() != 2 {
+		t.Errorf("expected version 2, got %d", team.Version())
+	}
+}
+
+func TestHandler_HandleSubmitStatusUpdate_ExistingTeam(t *testing.T) {
+	store := events.NewMemoryStore()
+	handler := NewHandler(store)
+
+	cmd := SubmitStatusUpdate{
+		TeamID:      mustTeamID(t, "team-1"),
+		ChannelName: "engineering",
+		Content:     mustContent(t, "First"),
+		Author:      mustAuthor(t, "Alice"),
+		SlackUser:   mustSlackUser(t, "alice"),
+		Timestamp:   time.Now(),
+	}
+
+	if _, err := handler.Handle(context.Background(), cmd); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := handler.Handle(context.Background(), cmd); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	stored := storedEvents(t, store)
+	if len(stored) != 3 {
+		t.Fatalf("expected 3 events (register + 2 updates), got %d", len(stored))
+	}
+
+	// Updates leave the team's stream at its registration
+	version, _ := store.AggregateVersion(context.Background(), "team-1")
+	if version != 1 {
+		t.Errorf("expected team version 1, got %d", version)
+	}
+	if stored[1].AggregateID == stored[2].AggregateID {
+		t.Errorf("expected each update in its own stream, both in %s", stored[1].AggregateID)
+	}
+	for i, event := range stored {
+		if event.Version != 1 {
+			t.Errorf("event %d: expected version 1, got %d", i, event.Version)
+		}
+	}
+}
+
+func TestHandler_RetriesConcurrencyConflict(t *testing.T) {
+	store := &conflictingStore{MemoryStore: events.NewMemoryStore(), conflicts: maxAttempts - 1}
+	handler := NewHandler(store)
+
+	cmd := RegisterTeam{
+		Name:         mustTeamName(t, "Engineering"),
+		SlackChannel: mustChannel(t, "#engineering"),
+	}
+
+	if _, err := handler.Handle(context.Background(), cmd); err != nil {
+		t.Fatalf("expected retry to succeed, got: %v", err)
+	}
+
+	stored := storedEvents(t, store)
+	if len(stored) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(stored))
+	}
+}
+
+func TestHandler_SurfacesConcurrencyConflict(t *testing.T) {
+	store := &conflictingStore{MemoryStore: events.NewMemoryStore(), conflicts: maxAttempts}
+	handler := NewHandler(store)
+
+	cmd := RegisterTeam{
+		Name:         mustTeamName(t, "Engineering"),
+		SlackChannel: mustChannel(t, "#engineering"),
+	}
+
+	_, err := handler.Handle(context.Background(), cmd)
+	if !errors.Is(err, events.ErrConcurrencyConflict) {
+		t.Fatalf("expected ErrConcurrencyConflict, got: %v", err)
+	}
+
+	stored := storedEvents(t, store)
+	if len(stored) != 0 {
+		t.Fatalf("expected no events, got %d", len(stored))
+	}
+}
+
+func TestHandler_AutoRegisterIsAtomic(t *testing.T) {
+	store := &conflictingStore{MemoryStore: events.NewMemoryStore(), conflicts: maxAttempts}
+	handler := NewHandler(store)
+
+	cmd := SubmitStatusUpdate{
+		TeamID:      mustTeamID(t, "C-NEW-TEAM"),
+		ChannelName: "new-team",
+		Content:     mustContent(t, "First update"),
+		Author:      mustAuthor(t, "Alice"),
+		SlackUser:   mustSlackUser(t, "U123"),
+		Timestamp:   time.Now(),
+	}
+
+	if _, err := handler.Handle(context.Background(), cmd); !errors.Is(err, events.ErrConcurrencyConflict) {
+		t.Fatalf("expected ErrConcurrencyConflict, got: %v", err)
+	}
+
+	// Neither the registration nor the update may be stored on its own
+	stored := storedEvents(t, store)
+	if len(stored) != 0 {
+		t.Fatalf("expected no events, got %d", len(stored))
+	}
+}
+
+func TestHandler_StampsMetadata(t *testing.T) {
+	store := events.NewMemoryStore()
+	handler := NewHandler(store)
+
+	metadata := events.Metadata{
+		CorrelationID: "slack-event-1",
+		CausationID:   "slack-event-1",
+		Actor:         "slack:U123",
+		Source:        "slackbot",
+		RequestID:     "req-1",
+	}
+	ctx := events.WithMetadata(context.Background(), metadata)
+
+	cmd := SubmitStatusUpdate{
+		TeamID:      mustTeamID(t, "C-META-TEAM"),
+		ChannelName: "meta-team",
+		Content:     mustContent(t, "Traced update"),
+		Author:      mustAuthor(t, "Alice"),
+		SlackUser:   mustSlackUser(t, "U123"),
+		Timestamp:   time.Now(),
+	}
+
+	if _, err := handler.Handle(ctx, cmd); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored := storedEvents(t, store)
+	if len(stored) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(stored))
+	}
+	for _, event := range stored {
+		got, err := event.ParseMetadata()
+		if err != nil {
+			t.Fatalf("failed to parse metadata: %v", err)
+		}
+		if got != metadata {
+			t.Errorf("event %s metadata = %+v, want %+v", event.Type, got, metadata)
+		}
+	}
+}
+
+// seedTeam stores a team.registered event for teamID
+func seedTeam(t *testing.T, store events.Store, teamID, name, channel string) {
+	t.Helper()
+	team, err := domain.NewTeam(mustTeamID(t, teamID), mustTeamName(t, name), mustChannel(t, channel))
+	if err != nil {
+		t.Fatalf("NewTeam: %v", err)
+	}
+	if _, err := NewTeamRepository(store, nil).Save(context.Background(), team); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+}
+
+func mustTeamID(t *testing.T, s string) domain.TeamID {
+	t.Helper()
+	v, err := domain.NewTeamID(s)
+	if err != nil {
+		t.Fatalf("NewTeamID: %v", err)
+	}
+	return v
+}
+
+func mustTeamName(t *testing.T, s string) domain.TeamName {
+	t.Helper()
+	v, err := domain.NewTeamName(s)
+	if err != nil {
+		t.Fatalf("NewTeamName: %v", err)
+	}
+	return v
+}
+
+func mustChannel(t *testing.T, s string) domain.SlackChannel {
+	t.Helper()
+	v, err := domain.NewSlackChannel(s)
+	if err != nil {
+		t.Fatalf("NewSlackChannel: %v", err)
+	}
+	return v
+}
+
+func mustContent(t *testing.T, s string) domain.UpdateContent {
+	t.Helper()
+	v, err := domain.NewUpdateContent(s)
+	if err != nil {
+		t.Fatalf("NewUpdateContent: %v", err)
+	}
+	return v
+}
+
+func mustAuthor(t *testing.T, s string) domain.Author {
+	t.Helper()
+	v, err := domain.NewAuthor(s)
+	if err != nil {
+		t.Fatalf("NewAuthor: %v", err)
+	}
+	return v
+}
+
+func mustSlackUser(t *testing.T, s string) domain.SlackUserID {
+	t.Helper()
+	v, err := domain.NewSlackUserID(s)
+	if err != nil {
+		t.Fatalf("NewSlackUserID: %v", err)
+	}
+	return v
 }
 
 func TestHandler_UnknownCommandType(t *testing.T) {
-	store := &MockEventStore{}
+	store := events.NewMemoryStore()
 	handler := NewHandler(store)
 
 	// Create a command type that doesn't match any handled types
@@ -165,3 +528,219 @@ func TestHandler_UnknownCommandType(t *testing.T) {
 	_ = handler
 	_ = unknownCmd
 }
+
+func TestHandler_EditAndDeleteStatusUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := events.NewMemoryStore()
+	handler := NewHandler(store)
+
+	submitted, err := handler.Handle(ctx, SubmitStatusUpdate{
+		TeamID:      mustTeamID(t, "team-1"),
+		ChannelName: "engineering",
+		Content:     mustContent(t, "Shipped it"),
+		Author:      mustAuthor(t, "Alice"),
+		SlackUser:   mustSlackUser(t, "alice"),
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	edit := EditStatusUpdate{
+		TeamID:    mustTeamID(t, "team-1"),
+		UpdateID:  submitted.UpdateID,
+		Content:   mustContent(t, "Shipped it to production"),
+		EditedBy:  mustSlackUser(t, "alice"),
+		Timestamp: time.Now(),
+	}
+	edited, err := handler.Handle(ctx, edit)
+	if err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	if len(edited.Events) != 1 || edited.Events[0].Type != events.StatusUpdateEdited || edited.Events[0].Version != 2 {
+		t.Fatalf("expected status_update.edited at version 2, got %+v", edited.Events)
+	}
+
+	// Edits to another team's update, or that change nothing, are rejected
+	otherTeam := edit
+	otherTeam.TeamID = mustTeamID(t, "team-2")
+	if _, err := handler.Handle(ctx, otherTeam); !errors.Is(err, domain.ErrUpdateNotFound) {
+		t.Errorf("expected ErrUpdateNotFound for another team, got %v", err)
+	}
+	if _, err := handler.Handle(ctx, edit); !errors.Is(err, domain.ErrUpdateUnchanged) {
+		t.Errorf("expected ErrUpdateUnchanged for a repeated edit, got %v", err)
+	}
+
+	deleted, err := handler.Handle(ctx, DeleteStatusUpdate{
+		TeamID:    mustTeamID(t, "team-1"),
+		UpdateID:  submitted.UpdateID,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(deleted.Events) != 1 || deleted.Events[0].Type != events.StatusUpdateDeleted {
+		t.Fatalf("expected status_update.deleted, got %+v", deleted.Events)
+	}
+
+	edit.Content = mustContent(t, "Too late")
+	if _, err := handler.Handle(ctx, edit); !errors.Is(err, domain.ErrUpdateNotFound) {
+		t.Errorf("expected ErrUpdateNotFound after delete, got %v", err)
+	}
+}
+
+func TestHandler_CommentOnStatusUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := events.NewMemoryStore()
+	handler := NewHandler(store)
+
+	submitted, err := handler.Handle(ctx, SubmitStatusUpdate{
+		TeamID:      mustTeamID(t, "team-1"),
+		ChannelName: "engineering",
+		Content:     mustContent(t, "Shipped it"),
+		Author:      mustAuthor(t, "Alice"),
+		SlackUser:   mustSlackUser(t, "alice"),
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	comment := CommentOnStatusUpdate{
+		TeamID:    mustTeamID(t, "team-1"),
+		UpdateID:  submitted.UpdateID,
+		Content:   mustContent(t, "Does that unblock the release?"),
+		Author:    mustAuthor(t, "Bob"),
+		SlackUser: mustSlackUser(t, "bob"),
+		Timestamp: time.Now(),
+	}
+	commented, err := handler.Handle(ctx, comment)
+	if err != nil {
+		t.Fatalf("comment: %v", err)
+	}
+	if commented.CommentID.String() == "" {
+		t.Error("expected the result to name the comment")
+	}
+	if len(commented.Events) != 1 || commented.Events[0].Type != events.StatusUpdateCommented || commented.Events[0].AggregateID != submitted.UpdateID.String() {
+		t.Fatalf("expected status_update.commented on the update's stream, got %+v", commented.Events)
+	}
+
+	stored := storedEvents(t, store)
+	data, err := events.Decode[events.StatusUpdateCommentedData](stored[len(stored)-1])
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if data.CommentID != commented.CommentID.String() || data.Author != "Bob" || data.SlackUser != "bob" {
+		t.Errorf("unexpected comment payload %+v", data)
+	}
+
+	// Comments on another team's update are rejected
+	comment.TeamID = mustTeamID(t, "team-2")
+	if _, err := handler.Handle(ctx, comment); !errors.Is(err, domain.ErrUpdateNotFound) {
+		t.Errorf("expected ErrUpdateNotFound for another team, got %v", err)
+	}
+}
+
+func TestHandler_HandleSubmitStructuredStatusUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := events.NewMemoryStore()
+	handler := NewHandler(store)
+
+	sections, err := domain.NewUpdateSections("Shipped login", "Start billing", "Waiting on API keys", domain.HealthAmber)
+	if err != nil {
+		t.Fatalf("NewUpdateSections: %v", err)
+	}
+
+	result, err := handler.Handle(ctx, SubmitStatusUpdate{
+		TeamID:      mustTeamID(t, "team-1"),
+		ChannelName: "engineering",
+		Sections:    sections,
+		Author:      mustAuthor(t, "Alice"),
+		SlackUser:   mustSlackUser(t, "alice"),
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	stored := storedEvents(t, store)
+	submitted := stored[len(stored)-1]
+	if submitted.SchemaVersion != 2 {
+		t.Errorf("expected schema version 2, got %d", submitted.SchemaVersion)
+	}
+	data, err := events.Decode[events.StatusUpdateSubmittedData](submitted)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := events.UpdateSectionsData{Done: "Shipped login", Next: "Start billing", Blockers: "Waiting on API keys", Health: "amber"}
+	if data.Format != events.UpdateFormatStructured || data.Sections == nil || *data.Sections != want {
+		t.Errorf("expected structured payload with %+v, got %+v", want, data)
+	}
+	if data.Content != sections.Text() {
+		t.Errorf("expected content rendered from the sections, got %q", data.Content)
+	}
+
+	update, err := handler.updates.Load(ctx, result.UpdateID)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if update.Sections() != sections {
+		t.Errorf("expected rehydrated sections %+v, got %+v", sections, update.Sections())
+	}
+
+	// A content edit, such as a Slack message_changed, must not drop the sections
+	_, err = handler.Handle(ctx, EditStatusUpdate{
+		TeamID:    mustTeamID(t, "team-1"),
+		UpdateID:  result.UpdateID,
+		Content:   mustContent(t, "Shipped login"),
+		Timestamp: time.Now(),
+	})
+	if !errors.Is(err, domain.ErrUpdateStructured) {
+		t.Errorf("expected ErrUpdateStructured for an edit, got %v", err)
+	}
+}
+
+func TestHandler_ChangeTeamHealth(t *testing.T) {
+	ctx := context.Background()
+	store := events.NewMemoryStore()
+	handler := NewHandler(store)
+	seedTeam(t, store, "team-1", "Engineering", "#engineering")
+
+	change := ChangeTeamHealth{
+		TeamID:    mustTeamID(t, "team-1"),
+		Health:    domain.HealthAmber,
+		Reason:    "Waiting on the vendor",
+		ChangedBy: mustSlackUser(t, "alice"),
+		Timestamp: time.Now(),
+	}
+	changed, err := handler.Handle(ctx, change)
+	if err != nil {
+		t.Fatalf("change health: %v", err)
+	}
+	if len(changed.Events) != 1 || changed.Events[0].Type != events.TeamHealthChanged || changed.Events[0].Version != 2 {
+		t.Fatalf("expected team.health_changed at version 2, got %+v", changed.Events)
+	}
+
+	if _, err := handler.Handle(ctx, change); !errors.Is(err, domain.ErrTeamUnchanged) {
+		t.Errorf("expected ErrTeamUnchanged for the same health, got %v", err)
+	}
+
+	change.Health = domain.HealthRed
+	change.Reason = ""
+	if _, err := handler.Handle(ctx, change); err != nil {
+		t.Fatalf("change health: %v", err)
+	}
+	stored := storedEvents(t, store)
+	data, err := events.Decode[events.TeamHealthChangedData](stored[len(stored)-1])
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if data.Health != "red" || data.PreviousHealth != "amber" || data.ChangedBy != "alice" {
+		t.Errorf("unexpected health payload %+v", data)
+	}
+
+	change.TeamID = mustTeamID(t, "team-2")
+	if _, err := handler.Handle(ctx, change); !errors.Is(err, domain.ErrTeamNotFound) {
+		t.Errorf("expected ErrTeamNotFound for an unknown team, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Aggregate snapshot metrics; the hit rate is the share of loads with result "hit"
+	snapshotLoadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "status_app",
+			Subsystem: "aggregates",
+			Name:      "snapshot_loads_total",
+			Help:      "Total number of aggregate loads by whether a usable snapshot was found",
+		},
+		[]string{"aggregate_type", "result"},
+	)
+
+	snapshotsSavedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "status_app",
+			Subsystem: "aggregates",
+			Name:      "snapshots_saved_total",
+			Help:      "Total number of aggregate snapshots saved",
+		},
+		[]string{"aggregate_type"},
+	)
+
+	eventsReplayedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "status_app",
+			Subsystem: "aggregates",
+			Name:      "events_replayed_total",
+			Help:      "Total number of events replayed to rehydrate aggregates",
+		},
+		[]string{"aggregate_type"},
+	)
+)
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"github.com/yourusername/status-app/internal/domain"
+	"github.com/yourusername/status-app/internal/events"
+)
+
+// Result describes what a handled command appended to the event log
+type Result struct {
+	TeamID    domain.TeamID
+	UpdateID  domain.UpdateID  // set by commands that create or change an update
+	CommentID domain.CommentID // set by commands that comment on an update
+	Events    []AppendedEvent
+}
+
+// AppendedEvent identifies an event a command appended and where it landed
+type AppendedEvent struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	AggregateID string `json:"aggregate_id"`
+	Version     int    `json:"version"`
+	Position    int64  `json:"position"`
+}
+
+// Position is the log position of the last event the command appended, or 0
+// if it appended none. Once the projections have reached it, reads reflect
+// the command.
+func (r Result) Position() int64 {
+	if len(r.Events) == 0 {
+		return 0
+	}
+	return r.Events[len(r.Events)-1].Position
+}
+
+// appendedEvents describes stored events, which carry the version and position
+// the store assigned them
+func appendedEvents(stored []*events.Event) []AppendedEvent {
+	appended := make([]AppendedEvent, 0, len(stored))
+	for _, event := range stored {
+		appended = append(appended, AppendedEvent{
+			ID:          event.ID,
+			Type:        event.Type,
+			AggregateID: event.AggregateID,
+			Version:     event.Version,
+			Position:    event.Position,
+		})
+	}
+	return appended
+}
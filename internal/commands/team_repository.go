@@ -0,0 +1,315 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/status-app/internal/domain"
+	"github.com/yourusername/status-app/internal/events"
+)
+
+const (
+	// teamAggregateType labels team snapshots and metrics
+	teamAggregateType = "team"
+
+	// teamSnapshotSchemaVersion is the format of teamSnapshotState. Bumping it
+	// when the state changes makes older snapshots misses, not errors.
+	// Version 2 added Health.
+	teamSnapshotSchemaVersion = 2
+
+	// snapshotInterval is how many events are stored between snapshots of a
+	// team. Team streams only hold registration, updates and health changes,
+	// so they stay short and the interval is sized to what they actually reach.
+	snapshotInterval = 20
+)
+
+// teamSnapshotState is the serialized form of domain.TeamSnapshot
+type teamSnapshotState struct {
+	Name         string `json:"name"`
+	SlackChannel string `json:"slack_channel"`
+	Health       string `json:"health,omitempty"`
+	Registered   bool   `json:"registered"`
+}
+
+// TeamRepository rehydrates domain.Team aggregates from their event streams
+// and appends the events their commands recorded. With a snapshot store it
+// snapshots teams every snapshotInterval events and rehydrates them from the
+// latest snapshot plus the events after it.
+type TeamRepository struct {
+	eventStore events.Store
+	snapshots  events.SnapshotStore // nil disables snapshots
+	upcasters  *events.Upcasters
+}
+
+func NewTeamRepository(eventStore events.Store, snapshots events.SnapshotStore) *TeamRepository {
+	return &TeamRepository{
+		eventStore: eventStore,
+		snapshots:  snapshots,
+		upcasters:  events.DefaultUpcasters,
+	}
+}
+
+// Load rebuilds the team from its latest snapshot and stream. A team without
+// events is returned unregistered rather than as an error, so it can be registered.
+func (r *TeamRepository) Load(ctx context.Context, id domain.TeamID) (*domain.Team, error) {
+	snapshot := r.loadSnapshot(ctx, id)
+
+	stored, err := r.eventStore.GetByAggregateIDFrom(ctx, id.String(), snapshot.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load team %s: %w", id, err)
+	}
+
+	upcast, err := r.upcasters.UpcastAll(stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load team %s: %w", id, err)
+	}
+
+	history := make([]domain.TeamEvent, 0, len(upcast))
+	for _, event := range upcast {
+		teamEvent, err := toTeamEvent(id, event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rehydrate team %s: %w", id, err)
+		}
+		history = append(history, teamEvent)
+	}
+	eventsReplayedTotal.WithLabelValues(teamAggregateType).Add(float64(len(history)))
+
+	return domain.RestoreTeam(snapshot, history...), nil
+}
+
+// Exists reports whether the team has any events, without loading them
+func (r *TeamRepository) Exists(ctx context.Context, id domain.TeamID) (bool, error) {
+	version, err := r.eventStore.AggregateVersion(ctx, id.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to check for team %s: %w", id, err)
+	}
+	return version > 0, nil
+}
+
+// loadSnapshot returns the team's latest usable snapshot, or an empty one at
+// version 0. Snapshots are only a cache, so failing to read one is logged and
+// the team is rehydrated from its first event instead.
+func (r *TeamRepository) loadSnapshot(ctx context.Context, id domain.TeamID) domain.TeamSnapshot {
+	empty := domain.TeamSnapshot{ID: id}
+	if r.snapshots == nil {
+		return empty
+	}
+
+	stored, err := r.snapshots.Load(ctx, id.String())
+	if err != nil {
+		log.Printf("failed to load snapshot of team %s, replaying all events: %v", id, err)
+		snapshotLoadsTotal.WithLabelValues(teamAggregateType, "error").Inc()
+		return empty
+	}
+	if stored == nil || stored.SchemaVersion != teamSnapshotSchemaVersion {
+		snapshotLoadsTotal.WithLabelValues(teamAggregateType, "miss").Inc()
+		return empty
+	}
+
+	snapshot, err := toTeamSnapshot(id, stored)
+	if err != nil {
+		log.Printf("ignoring unreadable snapshot of team %s: %v", id, err)
+		snapshotLoadsTotal.WithLabelValues(teamAggregateType, "error").Inc()
+		return empty
+	}
+	snapshotLoadsTotal.WithLabelValues(teamAggregateType, "hit").Inc()
+	return snapshot
+}
+
+// Save appends the team's changes as one batch at the versions following the
+// one it was loaded at, failing with events.ErrConcurrencyConflict if another
+// command appended to the team in between. It returns the stored events.
+func (r *TeamRepository) Save(ctx context.Context, team *domain.Team) ([]*events.Event, error) {
+	batch, err := r.pending(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	if len(batch) == 0 {
+		return nil, nil
+	}
+
+	if err := r.eventStore.AppendBatch(ctx, batch...); err != nil {
+		return nil, err
+	}
+
+	before := team.Version()
+	team.MarkCommitted()
+	if r.snapshots != nil && team.Version()/snapshotInterval > before/snapshotInterval {
+		r.saveSnapshot(ctx, team)
+	}
+	return batch, nil
+}
+
+// saveSnapshot stores the team's committed state. The events are already
+// stored, so a failure is only logged.
+func (r *TeamRepository) saveSnapshot(ctx context.Context, team *domain.Team) {
+	snapshot, err := team.Snapshot()
+	if err != nil {
+		log.Printf("failed to snapshot team %s: %v", team.ID(), err)
+		return
+	}
+
+	state, err := json.Marshal(teamSnapshotState{
+		Name:         snapshot.Name.String(),
+		SlackChannel: snapshot.SlackChannel.String(),
+		Health:       snapshot.Health.String(),
+		Registered:   snapshot.Registered,
+	})
+	if err != nil {
+		log.Printf("failed to marshal snapshot of team %s: %v", team.ID(), err)
+		return
+	}
+
+	err = r.snapshots.Save(ctx, &events.Snapshot{
+		AggregateID:   snapshot.ID.String(),
+		AggregateType: teamAggregateType,
+		Version:       snapshot.Version,
+		SchemaVersion: teamSnapshotSchemaVersion,
+		State:         state,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		log.Printf("failed to save snapshot of team %s at version %d: %v", team.ID(), snapshot.Version, err)
+		return
+	}
+	snapshotsSavedTotal.WithLabelValues(teamAggregateType).Inc()
+}
+
+// pending encodes the team's changes as the events to append at the versions
+// following the one it was loaded at
+func (r *TeamRepository) pending(ctx context.Context, team *domain.Team) ([]*events.Event, error) {
+	changes := team.Changes()
+	batch := make([]*events.Event, 0, len(changes))
+	for i, change := range changes {
+		event, err := r.toEvent(ctx, team.ID(), team.Version()+i+1, change)
+		if err != nil {
+			return nil, err
+		}
+		batch = append(batch, event)
+	}
+	return batch, nil
+}
+
+// toEvent encodes a recorded change as the event to store at version
+func (r *TeamRepository) toEvent(ctx context.Context, teamID domain.TeamID, version int, change domain.TeamEvent) (*events.Event, error) {
+	switch c := change.(type) {
+	case domain.TeamRegistered:
+		return newEvent(ctx, r.upcasters, events.TeamRegistered, teamID.String(), version, events.TeamRegisteredData{
+			TeamID:       c.TeamID.String(),
+			Name:         c.Name.String(),
+			SlackChannel: c.SlackChannel.String(),
+		})
+	case domain.TeamUpdated:
+		return newEvent(ctx, r.upcasters, events.TeamUpdated, teamID.String(), version, events.TeamUpdatedData{
+			TeamID:       c.TeamID.String(),
+			Name:         c.Name.String(),
+			SlackChannel: c.SlackChannel.String(),
+		})
+	case domain.TeamHealthChanged:
+		return newEvent(ctx, r.upcasters, events.TeamHealthChanged, teamID.String(), version, events.TeamHealthChangedData{
+			TeamID:         c.TeamID.String(),
+			Health:         c.Health.String(),
+			PreviousHealth: c.Previous.String(),
+			Reason:         c.Reason,
+			ChangedBy:      c.ChangedBy.String(),
+			Timestamp:      c.Timestamp,
+		})
+	default:
+		return nil, fmt.Errorf("unknown team change: %T", change)
+	}
+}
+
+// toTeamEvent decodes a stored event of a team's stream
+func toTeamEvent(teamID domain.TeamID, event *events.Event) (domain.TeamEvent, error) {
+	switch event.Type {
+	case events.TeamRegistered:
+		data, err := events.Decode[events.TeamRegisteredData](event)
+		if err != nil {
+			return nil, err
+		}
+		name, channel, err := teamDetails(data.Name, data.SlackChannel)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+		return domain.TeamRegistered{TeamID: teamID, Name: name, SlackChannel: channel}, nil
+
+	case events.TeamUpdated:
+		data, err := events.Decode[events.TeamUpdatedData](event)
+		if err != nil {
+			return nil, err
+		}
+		name, channel, err := teamDetails(data.Name, data.SlackChannel)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+		return domain.TeamUpdated{TeamID: teamID, Name: name, SlackChannel: channel}, nil
+
+	case events.TeamHealthChanged:
+		data, err := events.Decode[events.TeamHealthChangedData](event)
+		if err != nil {
+			return nil, err
+		}
+		health, err := domain.NewHealth(data.Health)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+		// The team's first rating has no previous health
+		var previous domain.Health
+		if data.PreviousHealth != "" {
+			if previous, err = domain.NewHealth(data.PreviousHealth); err != nil {
+				return nil, fmt.Errorf("event %s: %w", event.ID, err)
+			}
+		}
+		return domain.TeamHealthChanged{
+			TeamID:    teamID,
+			Health:    health,
+			Previous:  previous,
+			Reason:    data.Reason,
+			ChangedBy: optionalSlackUser(data.ChangedBy),
+			Timestamp: data.Timestamp,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected %s event %s in team stream", event.Type, event.ID)
+	}
+}
+
+// toTeamSnapshot decodes a stored team snapshot
+func toTeamSnapshot(id domain.TeamID, stored *events.Snapshot) (domain.TeamSnapshot, error) {
+	var state teamSnapshotState
+	if err := json.Unmarshal(stored.State, &state); err != nil {
+		return domain.TeamSnapshot{}, err
+	}
+
+	snapshot := domain.TeamSnapshot{ID: id, Registered: state.Registered, Version: stored.Version}
+	if state.Health != "" {
+		health, err := domain.NewHealth(state.Health)
+		if err != nil {
+			return domain.TeamSnapshot{}, err
+		}
+		snapshot.Health = health
+	}
+	if state.Registered {
+		name, channel, err := teamDetails(state.Name, state.SlackChannel)
+		if err != nil {
+			return domain.TeamSnapshot{}, err
+		}
+		snapshot.Name, snapshot.SlackChannel = name, channel
+	}
+	return snapshot, nil
+}
+
+func teamDetails(name, slackChannel string) (domain.TeamName, domain.SlackChannel, error) {
+	teamName, err := domain.NewTeamName(name)
+	if err != nil {
+		return domain.TeamName{}, domain.SlackChannel{}, err
+	}
+	channel, err := domain.NewSlackChannel(slackChannel)
+	if err != nil {
+		return domain.TeamName{}, domain.SlackChannel{}, err
+	}
+	return teamName, channel, nil
+}
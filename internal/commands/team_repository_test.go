@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yourusername/status-app/internal/domain"
+	"github.com/yourusername/status-app/internal/events"
+)
+
+// replayCountingStore records how many events rehydration read
+type replayCountingStore struct {
+	*events.MemoryStore
+	replayed int
+}
+
+func (s *replayCountingStore) GetByAggregateIDFrom(ctx context.Context, aggregateID string, version int) ([]*events.Event, error) {
+	stored, err := s.MemoryStore.GetByAggregateIDFrom(ctx, aggregateID, version)
+	s.replayed = len(stored)
+	return stored, err
+}
+
+func TestTeamRepository_Snapshots(t *testing.T) {
+	ctx := context.Background()
+	store := &replayCountingStore{MemoryStore: events.NewMemoryStore()}
+	snapshots := events.NewMemorySnapshotStore()
+	handler := NewHandlerWithSnapshots(store, snapshots)
+
+	if _, err := NewTeamRepository(store, snapshots).Save(ctx, mustTeam(t, "team-1", "Engineering")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Renames up to one past the snapshot interval
+	for i := 1; i <= snapshotInterval; i++ {
+		rename := UpdateTeam{
+			TeamID:       mustTeamID(t, "team-1"),
+			Name:         mustTeamName(t, fmt.Sprintf("Engineering %d", i)),
+			SlackChannel: mustChannel(t, "#engineering"),
+		}
+		if _, err := handler.Handle(ctx, rename); err != nil {
+			t.Fatalf("rename %d: %v", i, err)
+		}
+	}
+
+	snapshot, err := snapshots.Load(ctx, "team-1")
+	if err != nil {
+		t.Fatalf("Load snapshot: %v", err)
+	}
+	if snapshot == nil {
+		t.Fatal("expected a snapshot after snapshotInterval events")
+	}
+	if snapshot.Version != snapshotInterval {
+		t.Errorf("expected snapshot at version %d, got %d", snapshotInterval, snapshot.Version)
+	}
+
+	team, err := NewTeamRepository(store, snapshots).Load(ctx, mustTeamID(t, "team-1"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if store.replayed != 1 {
+		t.Errorf("expected only the event after the snapshot to be replayed, got %d", store.replayed)
+	}
+	if team.Version() != snapshotInterval+1 {
+		t.Errorf("expected version %d, got %d", snapshotInterval+1, team.Version())
+	}
+	want := fmt.Sprintf("Engineering %d", snapshotInterval)
+	if !team.IsRegistered() || team.Name().String() != want {
+		t.Errorf("expected registered team %s, got %q (registered %v)", want, team.Name(), team.IsRegistered())
+	}
+}
+
+func TestTeamRepository_SnapshotsHealth(t *testing.T) {
+	ctx := context.Background()
+	store := events.NewMemoryStore()
+	snapshots := events.NewMemorySnapshotStore()
+	handler := NewHandlerWithSnapshots(store, snapshots)
+
+	if _, err := NewTeamRepository(store, snapshots).Save(ctx, mustTeam(t, "team-1", "Engineering")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Alternate the health up to the snapshot interval
+	for i := 1; i < snapshotInterval; i++ {
+		health := domain.HealthAmber
+		if i%2 == 0 {
+			health = domain.HealthGreen
+		}
+		change := ChangeTeamHealth{TeamID: mustTeamID(t, "team-1"), Health: health, Timestamp: time.Now()}
+		if _, err := handler.Handle(ctx, change); err != nil {
+			t.Fatalf("change %d: %v", i, err)
+		}
+	}
+
+	if snapshot, _ := snapshots.Load(ctx, "team-1"); snapshot == nil {
+		t.Fatal("expected a snapshot after snapshotInterval events")
+	}
+	team, err := NewTeamRepository(store, snapshots).Load(ctx, mustTeamID(t, "team-1"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if team.Health() != domain.HealthAmber {
+		t.Errorf("expected amber health restored from the snapshot, got %q", team.Health())
+	}
+}
+
+func TestTeamRepository_IgnoresStaleSnapshots(t *testing.T) {
+	ctx := context.Background()
+	store := events.NewMemoryStore()
+	snapshots := events.NewMemorySnapshotStore()
+	repo := NewTeamRepository(store, snapshots)
+
+	if _, err := repo.Save(ctx, mustTeam(t, "team-1", "Engineering")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// A snapshot in a format this version does not know is skipped
+	snapshots.Save(ctx, &events.Snapshot{
+		AggregateID:   "team-1",
+		AggregateType: teamAggregateType,
+		Version:       1,
+		SchemaVersion: teamSnapshotSchemaVersion + 1,
+		State:         json.RawMessage(`{"title":"Old"}`),
+	})
+
+	loaded, err := repo.Load(ctx, mustTeamID(t, "team-1"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Name().String() != "Engineering" || loaded.Version() != 1 {
+		t.Errorf("expected Engineering at version 1, got %q at %d", loaded.Name(), loaded.Version())
+	}
+}
+
+func mustTeam(t *testing.T, teamID, name string) *domain.Team {
+	t.Helper()
+	team, err := domain.NewTeam(mustTeamID(t, teamID), mustTeamName(t, name), mustChannel(t, "#engineering"))
+	if err != nil {
+		t.Fatalf("NewTeam: %v", err)
+	}
+	return team
+}
+
+func TestUpdateRepository_LoadsOwnStream(t *testing.T) {
+	ctx := context.Background()
+	store := events.NewMemoryStore()
+	handler := NewHandler(store)
+
+	submit := SubmitStatusUpdate{
+		TeamID:      mustTeamID(t, "team-1"),
+		ChannelName: "engineering",
+		Content:     mustContent(t, "Shipped it"),
+		Author:      mustAuthor(t, "Alice"),
+		SlackUser:   mustSlackUser(t, "alice"),
+		Timestamp:   time.Now(),
+	}
+	if _, err := handler.Handle(ctx, submit); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	submitted, err := store.GetAll(ctx, events.StatusUpdateSubmitted, 0, 10)
+	if err != nil || len(submitted) != 1 {
+		t.Fatalf("expected 1 submitted event, got %d (%v)", len(submitted), err)
+	}
+
+	updateID, _ := domain.NewUpdateID(submitted[0].AggregateID)
+	update, err := NewUpdateRepository(store).Load(ctx, updateID)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !update.Exists() || update.TeamID().String() != "team-1" || update.Content().String() != "Shipped it" {
+		t.Errorf("unexpected update %+v", update)
+	}
+}
@@ -0,0 +1,359 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/status-app/internal/domain"
+	"github.com/yourusername/status-app/internal/events"
+)
+
+// UpdateRepository rehydrates domain.Update aggregates from their event
+// streams, keyed by update ID, and appends the events their commands recorded
+type UpdateRepository struct {
+	eventStore events.Store
+	upcasters  *events.Upcasters
+}
+
+func NewUpdateRepository(eventStore events.Store) *UpdateRepository {
+	return &UpdateRepository{
+		eventStore: eventStore,
+		upcasters:  events.DefaultUpcasters,
+	}
+}
+
+// Load rebuilds the update from its stream. An update without events is
+// returned with Exists() false.
+func (r *UpdateRepository) Load(ctx context.Context, id domain.UpdateID) (*domain.Update, error) {
+	stored, err := r.eventStore.GetByAggregateID(ctx, id.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load update %s: %w", id, err)
+	}
+
+	upcast, err := r.upcasters.UpcastAll(stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load update %s: %w", id, err)
+	}
+
+	history := make([]domain.UpdateEvent, 0, len(upcast))
+	for _, event := range upcast {
+		updateEvent, err := toUpdateEvent(event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rehydrate update %s: %w", id, err)
+		}
+		history = append(history, updateEvent)
+	}
+	return domain.RehydrateUpdate(id, history...), nil
+}
+
+// Save appends the update's changes, failing with events.ErrConcurrencyConflict
+// if another command appended to the update since it was loaded. It returns
+// the stored events.
+func (r *UpdateRepository) Save(ctx context.Context, update *domain.Update) ([]*events.Event, error) {
+	batch, err := r.pending(ctx, update)
+	if err != nil {
+		return nil, err
+	}
+	if len(batch) == 0 {
+		return nil, nil
+	}
+
+	if err := r.eventStore.AppendBatch(ctx, batch...); err != nil {
+		return nil, err
+	}
+	update.MarkCommitted()
+	return batch, nil
+}
+
+// pending encodes the update's changes as the events to append at the
+// versions following the one it was loaded at
+func (r *UpdateRepository) pending(ctx context.Context, update *domain.Update) ([]*events.Event, error) {
+	changes := update.Changes()
+	batch := make([]*events.Event, 0, len(changes))
+	for i, change := range changes {
+		event, err := r.toEvent(ctx, update.ID(), update.Version()+i+1, change)
+		if err != nil {
+			return nil, err
+		}
+		batch = append(batch, event)
+	}
+	return batch, nil
+}
+
+// toEvent encodes a recorded change as the event to store at version
+func (r *UpdateRepository) toEvent(ctx context.Context, updateID domain.UpdateID, version int, change domain.UpdateEvent) (*events.Event, error) {
+	switch c := change.(type) {
+	case domain.UpdateSubmitted:
+		format, sections := toSectionsData(c.Sections)
+		return newEvent(ctx, r.upcasters, events.StatusUpdateSubmitted, updateID.String(), version, events.StatusUpdateSubmittedData{
+			UpdateID:  c.UpdateID.String(),
+			TeamID:    c.TeamID.String(),
+			Content:   c.Content.String(),
+			Format:    format,
+			Sections:  sections,
+			Author:    c.Author.String(),
+			SlackUser: c.SlackUser.String(),
+			Timestamp: c.Timestamp,
+
+			SlackChannel:   c.SlackMessage.Channel(),
+			SlackTS:        c.SlackMessage.TS(),
+			SlackThreadTS:  c.SlackMessage.ThreadTS(),
+			SlackPermalink: c.SlackMessage.Permalink(),
+		})
+	case domain.UpdateEdited:
+		return newEvent(ctx, r.upcasters, events.StatusUpdateEdited, updateID.String(), version, events.StatusUpdateEditedData{
+			UpdateID:  c.UpdateID.String(),
+			TeamID:    c.TeamID.String(),
+			Content:   c.Content.String(),
+			EditedBy:  c.EditedBy.String(),
+			Timestamp: c.Timestamp,
+		})
+	case domain.UpdateDeleted:
+		return newEvent(ctx, r.upcasters, events.StatusUpdateDeleted, updateID.String(), version, events.StatusUpdateDeletedData{
+			UpdateID:  c.UpdateID.String(),
+			TeamID:    c.TeamID.String(),
+			DeletedBy: c.DeletedBy.String(),
+			Timestamp: c.Timestamp,
+		})
+	case domain.UpdateCommented:
+		return newEvent(ctx, r.upcasters, events.StatusUpdateCommented, updateID.String(), version, events.StatusUpdateCommentedData{
+			CommentID: c.CommentID.String(),
+			UpdateID:  c.UpdateID.String(),
+			TeamID:    c.TeamID.String(),
+			Content:   c.Content.String(),
+			Author:    c.Author.String(),
+			SlackUser: c.SlackUser.String(),
+			Timestamp: c.Timestamp,
+
+			SlackChannel:   c.SlackMessage.Channel(),
+			SlackTS:        c.SlackMessage.TS(),
+			SlackThreadTS:  c.SlackMessage.ThreadTS(),
+			SlackPermalink: c.SlackMessage.Permalink(),
+		})
+	default:
+		return nil, fmt.Errorf("unknown update change: %T", change)
+	}
+}
+
+// toUpdateEvent decodes a stored event of an update's stream
+func toUpdateEvent(event *events.Event) (domain.UpdateEvent, error) {
+	switch event.Type {
+	case events.StatusUpdateSubmitted:
+		data, err := events.Decode[events.StatusUpdateSubmittedData](event)
+		if err != nil {
+			return nil, err
+		}
+		submitted, err := toUpdateSubmitted(data)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+		return submitted, nil
+
+	case events.StatusUpdateEdited:
+		data, err := events.Decode[events.StatusUpdateEditedData](event)
+		if err != nil {
+			return nil, err
+		}
+		edited, err := toUpdateEdited(data)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+		return edited, nil
+
+	case events.StatusUpdateDeleted:
+		data, err := events.Decode[events.StatusUpdateDeletedData](event)
+		if err != nil {
+			return nil, err
+		}
+		deleted, err := toUpdateDeleted(data)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+		return deleted, nil
+
+	case events.StatusUpdateCommented:
+		data, err := events.Decode[events.StatusUpdateCommentedData](event)
+		if err != nil {
+			return nil, err
+		}
+		commented, err := toUpdateCommented(data)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+		return commented, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected %s event %s in update stream", event.Type, event.ID)
+	}
+}
+
+func toUpdateSubmitted(data events.StatusUpdateSubmittedData) (domain.UpdateSubmitted, error) {
+	id, err := domain.NewUpdateID(data.UpdateID)
+	if err != nil {
+		return domain.UpdateSubmitted{}, err
+	}
+	teamID, err := domain.NewTeamID(data.TeamID)
+	if err != nil {
+		return domain.UpdateSubmitted{}, err
+	}
+	sections, err := toUpdateSections(data)
+	if err != nil {
+		return domain.UpdateSubmitted{}, err
+	}
+	// The content of structured updates is rendered from their sections
+	content := sections.Content()
+	if sections.IsZero() {
+		content, err = domain.NewUpdateContent(data.Content)
+		if err != nil {
+			return domain.UpdateSubmitted{}, err
+		}
+	}
+	author, err := domain.NewAuthor(data.Author)
+	if err != nil {
+		return domain.UpdateSubmitted{}, err
+	}
+	slackUser, err := domain.NewSlackUserID(data.SlackUser)
+	if err != nil {
+		return domain.UpdateSubmitted{}, err
+	}
+	// Updates submitted before messages were recorded, or through the API,
+	// carry no Slack message
+	message, err := optionalSlackMessage(data.SlackChannel, data.SlackTS, data.SlackThreadTS, data.SlackPermalink)
+	if err != nil {
+		return domain.UpdateSubmitted{}, err
+	}
+	return domain.UpdateSubmitted{
+		UpdateID:     id,
+		TeamID:       teamID,
+		Content:      content,
+		Sections:     sections,
+		Author:       author,
+		SlackUser:    slackUser,
+		SlackMessage: message,
+		Timestamp:    data.Timestamp,
+	}, nil
+}
+
+// toSectionsData encodes the format and sections of a submitted update
+func toSectionsData(sections domain.UpdateSections) (string, *events.UpdateSectionsData) {
+	if sections.IsZero() {
+		return events.UpdateFormatText, nil
+	}
+	return events.UpdateFormatStructured, &events.UpdateSectionsData{
+		Done:     sections.Done(),
+		Next:     sections.Next(),
+		Blockers: sections.Blockers(),
+		Health:   sections.Health().String(),
+	}
+}
+
+// toUpdateSections decodes the sections of a structured update, or returns the
+// zero UpdateSections for free-text updates
+func toUpdateSections(data events.StatusUpdateSubmittedData) (domain.UpdateSections, error) {
+	if data.Format != events.UpdateFormatStructured || data.Sections == nil {
+		return domain.UpdateSections{}, nil
+	}
+	var health domain.Health
+	if data.Sections.Health != "" {
+		var err error
+		if health, err = domain.NewHealth(data.Sections.Health); err != nil {
+			return domain.UpdateSections{}, err
+		}
+	}
+	return domain.NewUpdateSections(data.Sections.Done, data.Sections.Next, data.Sections.Blockers, health)
+}
+
+func toUpdateEdited(data events.StatusUpdateEditedData) (domain.UpdateEdited, error) {
+	id, err := domain.NewUpdateID(data.UpdateID)
+	if err != nil {
+		return domain.UpdateEdited{}, err
+	}
+	teamID, err := domain.NewTeamID(data.TeamID)
+	if err != nil {
+		return domain.UpdateEdited{}, err
+	}
+	content, err := domain.NewUpdateContent(data.Content)
+	if err != nil {
+		return domain.UpdateEdited{}, err
+	}
+	return domain.UpdateEdited{
+		UpdateID:  id,
+		TeamID:    teamID,
+		Content:   content,
+		EditedBy:  optionalSlackUser(data.EditedBy),
+		Timestamp: data.Timestamp,
+	}, nil
+}
+
+func toUpdateDeleted(data events.StatusUpdateDeletedData) (domain.UpdateDeleted, error) {
+	id, err := domain.NewUpdateID(data.UpdateID)
+	if err != nil {
+		return domain.UpdateDeleted{}, err
+	}
+	teamID, err := domain.NewTeamID(data.TeamID)
+	if err != nil {
+		return domain.UpdateDeleted{}, err
+	}
+	return domain.UpdateDeleted{
+		UpdateID:  id,
+		TeamID:    teamID,
+		DeletedBy: optionalSlackUser(data.DeletedBy),
+		Timestamp: data.Timestamp,
+	}, nil
+}
+
+func toUpdateCommented(data events.StatusUpdateCommentedData) (domain.UpdateCommented, error) {
+	updateID, err := domain.NewUpdateID(data.UpdateID)
+	if err != nil {
+		return domain.UpdateCommented{}, err
+	}
+	teamID, err := domain.NewTeamID(data.TeamID)
+	if err != nil {
+		return domain.UpdateCommented{}, err
+	}
+	commentID, err := domain.NewCommentID(data.CommentID)
+	if err != nil {
+		return domain.UpdateCommented{}, err
+	}
+	content, err := domain.NewUpdateContent(data.Content)
+	if err != nil {
+		return domain.UpdateCommented{}, err
+	}
+	author, err := domain.NewAuthor(data.Author)
+	if err != nil {
+		return domain.UpdateCommented{}, err
+	}
+	message, err := optionalSlackMessage(data.SlackChannel, data.SlackTS, data.SlackThreadTS, data.SlackPermalink)
+	if err != nil {
+		return domain.UpdateCommented{}, err
+	}
+	return domain.UpdateCommented{
+		UpdateID:     updateID,
+		TeamID:       teamID,
+		CommentID:    commentID,
+		Content:      content,
+		Author:       author,
+		SlackUser:    optionalSlackUser(data.SlackUser),
+		SlackMessage: message,
+		Timestamp:    data.Timestamp,
+	}, nil
+}
+
+// optionalSlackMessage returns the Slack message recorded in an event, or the
+// zero SlackMessage if the event did not come from Slack
+func optionalSlackMessage(channel, ts, threadTS, permalink string) (domain.SlackMessage, error) {
+	if ts == "" {
+		return domain.SlackMessage{}, nil
+	}
+	return domain.NewSlackMessage(channel, ts, threadTS, permalink)
+}
+
+// optionalSlackUser returns the Slack user recorded in an event, or the zero
+// SlackUserID if the event did not come from Slack
+func optionalSlackUser(s string) domain.SlackUserID {
+	slackUser, err := domain.NewSlackUserID(s)
+	if err != nil {
+		return domain.SlackUserID{}
+	}
+	return slackUser
+}
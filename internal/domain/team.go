@@ -2,32 +2,130 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
+var (
+	// ErrTeamNotFound is returned for commands against a team that was never registered
+	ErrTeamNotFound = errors.New("team not found")
+
+	// ErrTeamAlreadyRegistered is returned when registering a team twice
+	ErrTeamAlreadyRegistered = errors.New("team is already registered")
+
+	// ErrTeamUnchanged is returned for changes that would leave the team as it is
+	ErrTeamUnchanged = errors.New("team is unchanged")
+)
+
+// maxHealthReasonLength bounds the reason given for a change of health
+const maxHealthReasonLength = 500
+
+// Team is the aggregate for a team. Its methods enforce
+// the business rules and record the resulting events as changes; Apply replays
+// stored events to rehydrate it.
 type Team struct {
 	id           TeamID
 	name         TeamName
 	slackChannel SlackChannel
+	health       Health // zero until the team's health is first rated
 	registered   bool
+
+	version int         // events applied from the team's stream
+	changes []TeamEvent // events recorded since the team was loaded
+}
+
+// TeamEvent is something that happened to a Team
+type TeamEvent interface {
+	teamEvent()
+}
+
+type TeamRegistered struct {
+	TeamID       TeamID
+	Name         TeamName
+	SlackChannel SlackChannel
 }
 
+type TeamUpdated struct {
+	TeamID       TeamID
+	Name         TeamName
+	SlackChannel SlackChannel
+}
+
+// TeamHealthChanged rates how the team's work is going. Previous is zero for
+// the team's first rating; Reason and ChangedBy are optional.
+type TeamHealthChanged struct {
+	TeamID    TeamID
+	Health    Health
+	Previous  Health
+	Reason    string
+	ChangedBy SlackUserID
+	Timestamp time.Time
+}
+
+func (TeamRegistered) teamEvent()    {}
+func (TeamUpdated) teamEvent()       {}
+func (TeamHealthChanged) teamEvent() {}
+
+// NewTeam registers a new team, recording TeamRegistered
 func NewTeam(id TeamID, name TeamName, slackChannel SlackChannel) (*Team, error) {
 	if id.IsEmpty() {
 		return nil, errors.New("team ID is required")
 	}
-	if name.String() == "" {
-		return nil, errors.New("team name is required")
+
+	team := &Team{id: id}
+	if err := team.Register(name, slackChannel); err != nil {
+		return nil, err
 	}
-	if slackChannel.String() == "" {
-		return nil, errors.New("slack channel is required")
+	return team, nil
+}
+
+// RehydrateTeam rebuilds a team from the events in its stream. A team without
+// events is returned unregistered.
+func RehydrateTeam(id TeamID, history ...TeamEvent) *Team {
+	return RestoreTeam(TeamSnapshot{ID: id}, history...)
+}
+
+// TeamSnapshot is a Team's state as of Version, letting rehydration start
+// from it rather than from the team's first event
+type TeamSnapshot struct {
+	ID           TeamID
+	Name         TeamName
+	SlackChannel SlackChannel
+	Health       Health
+	Registered   bool
+	Version      int
+}
+
+// RestoreTeam rebuilds a team from a snapshot and the events stored after it
+func RestoreTeam(snapshot TeamSnapshot, history ...TeamEvent) *Team {
+	team := &Team{
+		id:           snapshot.ID,
+		name:         snapshot.Name,
+		slackChannel: snapshot.SlackChannel,
+		health:       snapshot.Health,
+		registered:   snapshot.Registered,
+		version:      snapshot.Version,
 	}
+	for _, event := range history {
+		team.Apply(event)
+	}
+	return team
+}
 
-	return &Team{
-		id:           id,
-		name:         name,
-		slackChannel: slackChannel,
-		registered:   true,
+// Snapshot captures the team's stored state; changes not yet committed are
+// not part of any version, so they must be committed first
+func (t *Team) Snapshot() (TeamSnapshot, error) {
+	if len(t.changes) > 0 {
+		return TeamSnapshot{}, errors.New("cannot snapshot a team with uncommitted changes")
+	}
+	return TeamSnapshot{
+		ID:           t.id,
+		Name:         t.name,
+		SlackChannel: t.slackChannel,
+		Health:       t.health,
+		Registered:   t.registered,
+		Version:      t.version,
 	}, nil
 }
 
@@ -43,80 +141,131 @@ func (t *Team) SlackChannel() SlackChannel {
 	return t.slackChannel
 }
 
+// Health returns the team's current health, or the zero Health if it was
+// never rated
+func (t *Team) Health() Health {
+	return t.health
+}
+
 func (t *Team) IsRegistered() bool {
 	return t.registered
 }
 
-func (t *Team) Rename(newName TeamName) error {
-	if newName.String() == "" {
-		return errors.New("new team name cannot be empty")
-	}
-	if newName.String() == t.name.String() {
-		return errors.New("new team name is the same as current name")
-	}
-	t.name = newName
-	return nil
+// Version returns the number of stored events the team was rehydrated from
+func (t *Team) Version() int {
+	return t.version
 }
 
-type Update struct {
-	id        UpdateID
-	teamID    TeamID
-	content   UpdateContent
-	author    Author
-	slackUser SlackUserID
-	timestamp time.Time
+// Changes returns the events recorded since the team was loaded
+func (t *Team) Changes() []TeamEvent {
+	return t.changes
 }
 
-func NewUpdate(id UpdateID, teamID TeamID, content UpdateContent, author Author, slackUser SlackUserID, timestamp time.Time) (*Update, error) {
-	if id.String() == "" {
-		return nil, errors.New("update ID is required")
-	}
-	if teamID.IsEmpty() {
-		return nil, errors.New("team ID is required")
-	}
-	if content.String() == "" {
-		return nil, errors.New("content is required")
-	}
-	if author.String() == "" {
-		return nil, errors.New("author is required")
-	}
-	if slackUser.String() == "" {
-		return nil, errors.New("slack user is required")
-	}
-	if timestamp.IsZero() {
-		return nil, errors.New("timestamp is required")
-	}
+// MarkCommitted records that the team's changes were stored
+func (t *Team) MarkCommitted() {
+	t.version += len(t.changes)
+	t.changes = nil
+}
 
-	return &Update{
-		id:        id,
-		teamID:    teamID,
-		content:   content,
-		author:    author,
-		slackUser: slackUser,
-		timestamp: timestamp,
-	}, nil
+// Apply replays a stored event. Stored events already happened, so Apply
+// changes state without checking any rules.
+func (t *Team) Apply(event TeamEvent) {
+	t.when(event)
+	t.version++
 }
 
-func (u *Update) ID() UpdateID {
-	return u.id
+// record applies a new event and keeps it as a change to store
+func (t *Team) record(event TeamEvent) {
+	t.when(event)
+	t.changes = append(t.changes, event)
 }
 
-func (u *Update) TeamID() TeamID {
-	return u.teamID
+func (t *Team) when(event TeamEvent) {
+	switch e := event.(type) {
+	case TeamRegistered:
+		t.name = e.Name
+		t.slackChannel = e.SlackChannel
+		t.registered = true
+	case TeamUpdated:
+		t.name = e.Name
+		t.slackChannel = e.SlackChannel
+	case TeamHealthChanged:
+		t.health = e.Health
+	}
 }
 
-func (u *Update) Content() UpdateContent {
-	return u.content
+// Register registers a team loaded without history, recording TeamRegistered
+func (t *Team) Register(name TeamName, slackChannel SlackChannel) error {
+	if t.registered {
+		return ErrTeamAlreadyRegistered
+	}
+	if name.String() == "" {
+		return errors.New("team name is required")
+	}
+	if slackChannel.String() == "" {
+		return errors.New("slack channel is required")
+	}
+
+	t.record(TeamRegistered{TeamID: t.id, Name: name, SlackChannel: slackChannel})
+	return nil
 }
 
-func (u *Update) Author() Author {
-	return u.author
+func (t *Team) Rename(newName TeamName) error {
+	if newName.String() == "" {
+		return errors.New("new team name cannot be empty")
+	}
+	if newName.String() == t.name.String() {
+		return fmt.Errorf("%w: new team name is the same as current name", ErrTeamUnchanged)
+	}
+	return t.Update(newName, t.slackChannel)
 }
 
-func (u *Update) SlackUser() SlackUserID {
-	return u.slackUser
+// Update changes the team's name and Slack channel, recording TeamUpdated
+func (t *Team) Update(name TeamName, slackChannel SlackChannel) error {
+	if !t.registered {
+		return fmt.Errorf("%w: %s", ErrTeamNotFound, t.id)
+	}
+	if name.String() == "" {
+		return errors.New("team name is required")
+	}
+	if slackChannel.String() == "" {
+		return errors.New("slack channel is required")
+	}
+	if name == t.name && slackChannel == t.slackChannel {
+		return fmt.Errorf("%w: name and slack channel are the same as current ones", ErrTeamUnchanged)
+	}
+
+	t.record(TeamUpdated{TeamID: t.id, Name: name, SlackChannel: slackChannel})
+	return nil
 }
 
-func (u *Update) Timestamp() time.Time {
-	return u.timestamp
+// ChangeHealth rates how the team's work is going, recording TeamHealthChanged.
+// changedBy is empty when the change did not come from Slack.
+func (t *Team) ChangeHealth(health Health, reason string, changedBy SlackUserID, timestamp time.Time) error {
+	if !t.registered {
+		return fmt.Errorf("%w: %s", ErrTeamNotFound, t.id)
+	}
+	if health.IsZero() {
+		return errors.New("health is required")
+	}
+	reason = strings.TrimSpace(reason)
+	if len(reason) > maxHealthReasonLength {
+		return fmt.Errorf("reason must be %d characters or less", maxHealthReasonLength)
+	}
+	if timestamp.IsZero() {
+		return errors.New("timestamp is required")
+	}
+	if health == t.health {
+		return fmt.Errorf("%w: health is already %s", ErrTeamUnchanged, health)
+	}
+
+	t.record(TeamHealthChanged{
+		TeamID:    t.id,
+		Health:    health,
+		Previous:  t.health,
+		Reason:    reason,
+		ChangedBy: changedBy,
+		Timestamp: timestamp,
+	})
+	return nil
 }
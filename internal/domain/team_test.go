@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -74,6 +75,126 @@ func TestTeam_Rename(t *testing.T) {
 	}
 }
 
+func TestTeam_RecordsChanges(t *testing.T) {
+	teamID, _ := NewTeamID("team-123")
+	channel, _ := NewSlackChannel("C12345")
+
+	team, err := NewTeam(teamID, mustTeamName("Engineering"), channel)
+	if err != nil {
+		t.Fatalf("NewTeam() error = %v", err)
+	}
+	if err := team.Rename(mustTeamName("Product")); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+
+	changes := team.Changes()
+	if len(changes) != 2 {
+		t.Fatalf("len(Changes()) = %d, want 2", len(changes))
+	}
+	if _, ok := changes[0].(TeamRegistered); !ok {
+		t.Errorf("Changes()[0] = %T, want TeamRegistered", changes[0])
+	}
+	if updated, ok := changes[1].(TeamUpdated); !ok || updated.Name.String() != "Product" {
+		t.Errorf("Changes()[1] = %#v, want TeamUpdated to Product", changes[1])
+	}
+
+	team.MarkCommitted()
+	if team.Version() != 2 || len(team.Changes()) != 0 {
+		t.Errorf("after MarkCommitted() version = %d, changes = %d, want 2, 0", team.Version(), len(team.Changes()))
+	}
+}
+
+func TestRehydrateTeam(t *testing.T) {
+	teamID, _ := NewTeamID("team-123")
+	channel, _ := NewSlackChannel("C12345")
+
+	team := RehydrateTeam(teamID,
+		TeamRegistered{TeamID: teamID, Name: mustTeamName("Engineering"), SlackChannel: channel},
+		TeamUpdated{TeamID: teamID, Name: mustTeamName("Product"), SlackChannel: channel},
+	)
+
+	if !team.IsRegistered() {
+		t.Error("team.IsRegistered() = false, want true")
+	}
+	if team.Name().String() != "Product" {
+		t.Errorf("team.Name() = %v, want Product", team.Name())
+	}
+	if team.Version() != 2 {
+		t.Errorf("team.Version() = %d, want 2", team.Version())
+	}
+	if len(team.Changes()) != 0 {
+		t.Errorf("len(team.Changes()) = %d, want 0", len(team.Changes()))
+	}
+	if err := team.Register(mustTeamName("Engineering"), channel); !errors.Is(err, ErrTeamAlreadyRegistered) {
+		t.Errorf("Register() error = %v, want ErrTeamAlreadyRegistered", err)
+	}
+}
+
+func TestTeam_RequiresRegistration(t *testing.T) {
+	teamID, _ := NewTeamID("team-123")
+	channel, _ := NewSlackChannel("C12345")
+	team := RehydrateTeam(teamID)
+
+	if err := team.Update(mustTeamName("Product"), channel); !errors.Is(err, ErrTeamNotFound) {
+		t.Errorf("Update() error = %v, want ErrTeamNotFound", err)
+	}
+
+	if len(team.Changes()) != 0 {
+		t.Errorf("len(team.Changes()) = %d, want 0", len(team.Changes()))
+	}
+}
+
+func TestTeam_ChangeHealth(t *testing.T) {
+	teamID, _ := NewTeamID("team-123")
+	channel, _ := NewSlackChannel("C12345")
+	changedBy, _ := NewSlackUserID("U12345")
+	now := time.Now()
+
+	team, _ := NewTeam(teamID, mustTeamName("Engineering"), channel)
+	team.MarkCommitted()
+
+	if err := team.ChangeHealth(Health{}, "", changedBy, now); err == nil {
+		t.Error("ChangeHealth() without health succeeded, want error")
+	}
+	if err := team.ChangeHealth(HealthAmber, "  Waiting on the vendor  ", changedBy, now); err != nil {
+		t.Fatalf("ChangeHealth() error = %v", err)
+	}
+	if err := team.ChangeHealth(HealthAmber, "Still waiting", changedBy, now); !errors.Is(err, ErrTeamUnchanged) {
+		t.Errorf("ChangeHealth() to the same health error = %v, want ErrTeamUnchanged", err)
+	}
+	if err := team.ChangeHealth(HealthRed, "", SlackUserID{}, now); err != nil {
+		t.Fatalf("ChangeHealth() error = %v", err)
+	}
+
+	if team.Health() != HealthRed {
+		t.Errorf("team.Health() = %v, want red", team.Health())
+	}
+	changes := team.Changes()
+	if len(changes) != 2 {
+		t.Fatalf("len(Changes()) = %d, want 2", len(changes))
+	}
+	first := changes[0].(TeamHealthChanged)
+	if first.Health != HealthAmber || !first.Previous.IsZero() || first.Reason != "Waiting on the vendor" {
+		t.Errorf("Changes()[0] = %#v, want amber from no health with the trimmed reason", first)
+	}
+	if second := changes[1].(TeamHealthChanged); second.Previous != HealthAmber {
+		t.Errorf("Changes()[1].Previous = %v, want amber", second.Previous)
+	}
+
+	rehydrated := RehydrateTeam(teamID,
+		TeamRegistered{TeamID: teamID, Name: mustTeamName("Engineering"), SlackChannel: channel},
+		first,
+	)
+	if rehydrated.Health() != HealthAmber {
+		t.Errorf("rehydrated team.Health() = %v, want amber", rehydrated.Health())
+	}
+
+	unregistered := RehydrateTeam(teamID)
+	if err := unregistered.ChangeHealth(HealthGreen, "", changedBy, now); !errors.Is(err, ErrTeamNotFound) {
+		t.Errorf("ChangeHealth() on unregistered team error = %v, want ErrTeamNotFound", err)
+	}
+}
+
 func mustTeamName(s string) TeamName {
 	name, err := NewTeamName(s)
 	if err != nil {
@@ -117,7 +238,7 @@ func TestNewUpdate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			update, err := NewUpdate(tt.id, tt.teamID, tt.content, tt.author, tt.slackUser, tt.timestamp)
+			update, err := NewUpdate(tt.id, tt.teamID, tt.content, tt.author, tt.slackUser, SlackMessage{}, tt.timestamp)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewUpdate() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,330 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrUpdateNotFound is returned for commands against an update that was
+	// never submitted, was deleted, or belongs to another team
+	ErrUpdateNotFound = errors.New("status update not found")
+
+	// ErrUpdateUnchanged is returned for edits that would leave the content as it is
+	ErrUpdateUnchanged = errors.New("status update is unchanged")
+
+	// ErrUpdateStructured is returned for content edits of structured updates,
+	// which would drop their sections
+	ErrUpdateStructured = errors.New("structured status updates cannot be edited as free text")
+)
+
+// Update is the aggregate for a single status update, with its own stream so
+// a team's stream does not grow with every update it posts
+type Update struct {
+	id        UpdateID
+	teamID    TeamID
+	content   UpdateContent
+	author    Author
+	slackUser SlackUserID
+	message   SlackMessage
+	sections  UpdateSections // zero for free-text updates
+	timestamp time.Time
+	editedAt  time.Time // zero until the update is edited
+	deleted   bool
+
+	version int           // events applied from the update's stream
+	changes []UpdateEvent // events recorded since the update was loaded
+}
+
+// UpdateEvent is something that happened to an Update
+type UpdateEvent interface {
+	updateEvent()
+}
+
+// UpdateSubmitted starts an update. Structured updates carry their Sections,
+// with Content rendered from them.
+type UpdateSubmitted struct {
+	UpdateID     UpdateID
+	TeamID       TeamID
+	Content      UpdateContent
+	Sections     UpdateSections // zero for free-text updates
+	Author       Author
+	SlackUser    SlackUserID
+	SlackMessage SlackMessage // zero unless posted from Slack
+	Timestamp    time.Time
+}
+
+func (UpdateSubmitted) updateEvent() {}
+
+// UpdateEdited replaces the update's content. Structured updates cannot be
+// edited; edits stored before that was enforced turned them into free text.
+// EditedBy is empty when the edit did not come from Slack.
+type UpdateEdited struct {
+	UpdateID  UpdateID
+	TeamID    TeamID
+	Content   UpdateContent
+	EditedBy  SlackUserID
+	Timestamp time.Time
+}
+
+func (UpdateEdited) updateEvent() {}
+
+// UpdateDeleted removes the update. DeletedBy is empty when the deletion did
+// not come from Slack.
+type UpdateDeleted struct {
+	UpdateID  UpdateID
+	TeamID    TeamID
+	DeletedBy SlackUserID
+	Timestamp time.Time
+}
+
+func (UpdateDeleted) updateEvent() {}
+
+// UpdateCommented adds a comment to the update's discussion, such as a reply
+// in the Slack thread under it
+type UpdateCommented struct {
+	UpdateID     UpdateID
+	TeamID       TeamID
+	CommentID    CommentID
+	Content      UpdateContent
+	Author       Author
+	SlackUser    SlackUserID
+	SlackMessage SlackMessage // zero unless posted from Slack
+	Timestamp    time.Time
+}
+
+func (UpdateCommented) updateEvent() {}
+
+// NewUpdate submits a status update, recording UpdateSubmitted. message is the
+// Slack message the update was posted as, or the zero SlackMessage.
+func NewUpdate(id UpdateID, teamID TeamID, content UpdateContent, author Author, slackUser SlackUserID, message SlackMessage, timestamp time.Time) (*Update, error) {
+	return newUpdate(id, teamID, content, UpdateSections{}, author, slackUser, message, timestamp)
+}
+
+// NewStructuredUpdate submits a structured status update, recording
+// UpdateSubmitted with content rendered from sections
+func NewStructuredUpdate(id UpdateID, teamID TeamID, sections UpdateSections, author Author, slackUser SlackUserID, message SlackMessage, timestamp time.Time) (*Update, error) {
+	if sections.IsZero() {
+		return nil, errors.New("sections are required")
+	}
+	return newUpdate(id, teamID, sections.Content(), sections, author, slackUser, message, timestamp)
+}
+
+func newUpdate(id UpdateID, teamID TeamID, content UpdateContent, sections UpdateSections, author Author, slackUser SlackUserID, message SlackMessage, timestamp time.Time) (*Update, error) {
+	if id.String() == "" {
+		return nil, errors.New("update ID is required")
+	}
+	if teamID.IsEmpty() {
+		return nil, errors.New("team ID is required")
+	}
+	if content.String() == "" {
+		return nil, errors.New("content is required")
+	}
+	if author.String() == "" {
+		return nil, errors.New("author is required")
+	}
+	if slackUser.String() == "" {
+		return nil, errors.New("slack user is required")
+	}
+	if timestamp.IsZero() {
+		return nil, errors.New("timestamp is required")
+	}
+
+	update := &Update{id: id}
+	update.record(UpdateSubmitted{
+		UpdateID:     id,
+		TeamID:       teamID,
+		Content:      content,
+		Sections:     sections,
+		Author:       author,
+		SlackUser:    slackUser,
+		SlackMessage: message,
+		Timestamp:    timestamp,
+	})
+	return update, nil
+}
+
+// RehydrateUpdate rebuilds an update from the events in its stream
+func RehydrateUpdate(id UpdateID, history ...UpdateEvent) *Update {
+	update := &Update{id: id}
+	for _, event := range history {
+		update.Apply(event)
+	}
+	return update
+}
+
+func (u *Update) ID() UpdateID {
+	return u.id
+}
+
+func (u *Update) TeamID() TeamID {
+	return u.teamID
+}
+
+func (u *Update) Content() UpdateContent {
+	return u.content
+}
+
+func (u *Update) Author() Author {
+	return u.author
+}
+
+func (u *Update) SlackUser() SlackUserID {
+	return u.slackUser
+}
+
+// SlackMessage returns the Slack message the update was posted as, or the
+// zero SlackMessage
+func (u *Update) SlackMessage() SlackMessage {
+	return u.message
+}
+
+// Sections returns the sections of a structured update, or the zero
+// UpdateSections for free-text updates
+func (u *Update) Sections() UpdateSections {
+	return u.sections
+}
+
+func (u *Update) Timestamp() time.Time {
+	return u.timestamp
+}
+
+// EditedAt returns when the update was last edited, or the zero time
+func (u *Update) EditedAt() time.Time {
+	return u.editedAt
+}
+
+// Exists reports whether the update was submitted
+func (u *Update) Exists() bool {
+	return u.version > 0 || len(u.changes) > 0
+}
+
+// IsDeleted reports whether the update was deleted
+func (u *Update) IsDeleted() bool {
+	return u.deleted
+}
+
+// Edit replaces the content of one of teamID's free-text updates, recording
+// UpdateEdited
+func (u *Update) Edit(teamID TeamID, content UpdateContent, editedBy SlackUserID, timestamp time.Time) error {
+	if err := u.requireLive(teamID); err != nil {
+		return err
+	}
+	if !u.sections.IsZero() {
+		return fmt.Errorf("%w: %s", ErrUpdateStructured, u.id)
+	}
+	if content.String() == "" {
+		return errors.New("content is required")
+	}
+	if content == u.content {
+		return fmt.Errorf("%w: content is the same as the current content", ErrUpdateUnchanged)
+	}
+
+	u.record(UpdateEdited{
+		UpdateID:  u.id,
+		TeamID:    u.teamID,
+		Content:   content,
+		EditedBy:  editedBy,
+		Timestamp: timestamp,
+	})
+	return nil
+}
+
+// Delete removes one of teamID's updates, recording UpdateDeleted
+func (u *Update) Delete(teamID TeamID, deletedBy SlackUserID, timestamp time.Time) error {
+	if err := u.requireLive(teamID); err != nil {
+		return err
+	}
+
+	u.record(UpdateDeleted{
+		UpdateID:  u.id,
+		TeamID:    u.teamID,
+		DeletedBy: deletedBy,
+		Timestamp: timestamp,
+	})
+	return nil
+}
+
+// Comment adds a comment to one of teamID's updates, recording UpdateCommented
+func (u *Update) Comment(teamID TeamID, id CommentID, content UpdateContent, author Author, slackUser SlackUserID, message SlackMessage, timestamp time.Time) error {
+	if err := u.requireLive(teamID); err != nil {
+		return err
+	}
+	if id.String() == "" {
+		return errors.New("comment ID is required")
+	}
+	if content.String() == "" {
+		return errors.New("content is required")
+	}
+	if author.String() == "" {
+		return errors.New("author is required")
+	}
+
+	u.record(UpdateCommented{
+		UpdateID:     u.id,
+		TeamID:       u.teamID,
+		CommentID:    id,
+		Content:      content,
+		Author:       author,
+		SlackUser:    slackUser,
+		SlackMessage: message,
+		Timestamp:    timestamp,
+	})
+	return nil
+}
+
+// requireLive checks that the update was submitted by teamID and not deleted
+func (u *Update) requireLive(teamID TeamID) error {
+	if !u.Exists() || u.deleted || u.teamID != teamID {
+		return fmt.Errorf("%w: %s", ErrUpdateNotFound, u.id)
+	}
+	return nil
+}
+
+// Version returns the number of stored events the update was rehydrated from
+func (u *Update) Version() int {
+	return u.version
+}
+
+// Changes returns the events recorded since the update was loaded
+func (u *Update) Changes() []UpdateEvent {
+	return u.changes
+}
+
+// MarkCommitted records that the update's changes were stored
+func (u *Update) MarkCommitted() {
+	u.version += len(u.changes)
+	u.changes = nil
+}
+
+// Apply replays a stored event without checking any rules
+func (u *Update) Apply(event UpdateEvent) {
+	u.when(event)
+	u.version++
+}
+
+// record applies a new event and keeps it as a change to store
+func (u *Update) record(event UpdateEvent) {
+	u.when(event)
+	u.changes = append(u.changes, event)
+}
+
+func (u *Update) when(event UpdateEvent) {
+	switch e := event.(type) {
+	case UpdateSubmitted:
+		u.teamID = e.TeamID
+		u.content = e.Content
+		u.author = e.Author
+		u.slackUser = e.SlackUser
+		u.message = e.SlackMessage
+		u.sections = e.Sections
+		u.timestamp = e.Timestamp
+	case UpdateEdited:
+		u.content = e.Content
+		u.sections = UpdateSections{}
+		u.editedAt = e.Timestamp
+	case UpdateDeleted:
+		u.deleted = true
+	}
+}
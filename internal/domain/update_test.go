@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpdate_RecordsSubmission(t *testing.T) {
+	id, _ := NewUpdateID("update-123")
+	teamID, _ := NewTeamID("team-123")
+	content, _ := NewUpdateContent("Working on feature X")
+	author, _ := NewAuthor("john.doe")
+	slackUser, _ := NewSlackUserID("U12345")
+	message, _ := NewSlackMessage("C123", "1700000000.000100", "", "https://example.slack.com/archives/C123/p1700000000000100")
+	timestamp := time.Now()
+
+	update, err := NewUpdate(id, teamID, content, author, slackUser, message, timestamp)
+	if err != nil {
+		t.Fatalf("NewUpdate() error = %v", err)
+	}
+
+	changes := update.Changes()
+	if len(changes) != 1 {
+		t.Fatalf("len(Changes()) = %d, want 1", len(changes))
+	}
+	submitted, ok := changes[0].(UpdateSubmitted)
+	if !ok {
+		t.Fatalf("Changes()[0] = %T, want UpdateSubmitted", changes[0])
+	}
+
+	rehydrated := RehydrateUpdate(id, submitted)
+	if !rehydrated.Exists() || rehydrated.Version() != 1 {
+		t.Errorf("rehydrated Exists() = %v, Version() = %d, want true, 1", rehydrated.Exists(), rehydrated.Version())
+	}
+	if rehydrated.TeamID() != teamID || rehydrated.Content() != content {
+		t.Errorf("rehydrated update = %q for %q, want %q for %q", rehydrated.Content(), rehydrated.TeamID(), content, teamID)
+	}
+	if rehydrated.SlackMessage() != message {
+		t.Errorf("rehydrated SlackMessage() = %+v, want %+v", rehydrated.SlackMessage(), message)
+	}
+	if len(rehydrated.Changes()) != 0 {
+		t.Errorf("len(rehydrated.Changes()) = %d, want 0", len(rehydrated.Changes()))
+	}
+}
+
+func TestRehydrateUpdate_Missing(t *testing.T) {
+	id, _ := NewUpdateID("update-123")
+	if RehydrateUpdate(id).Exists() {
+		t.Error("Exists() = true for an update without events")
+	}
+}
+
+func submittedUpdate(t *testing.T) *Update {
+	t.Helper()
+	id, _ := NewUpdateID("update-123")
+	teamID, _ := NewTeamID("team-123")
+	content, _ := NewUpdateContent("Working on feature X")
+	author, _ := NewAuthor("john.doe")
+	slackUser, _ := NewSlackUserID("U12345")
+
+	update, err := NewUpdate(id, teamID, content, author, slackUser, SlackMessage{}, time.Now())
+	if err != nil {
+		t.Fatalf("NewUpdate() error = %v", err)
+	}
+	update.MarkCommitted()
+	return update
+}
+
+func TestUpdate_Edit(t *testing.T) {
+	teamID, _ := NewTeamID("team-123")
+	otherTeam, _ := NewTeamID("team-456")
+	editor, _ := NewSlackUserID("U12345")
+	fixed, _ := NewUpdateContent("Working on feature Y")
+	editedAt := time.Now()
+
+	t.Run("records the new content", func(t *testing.T) {
+		update := submittedUpdate(t)
+		if err := update.Edit(teamID, fixed, editor, editedAt); err != nil {
+			t.Fatalf("Edit() error = %v", err)
+		}
+		if update.Content() != fixed || !update.EditedAt().Equal(editedAt) {
+			t.Errorf("Content() = %q edited at %v, want %q at %v", update.Content(), update.EditedAt(), fixed, editedAt)
+		}
+		if len(update.Changes()) != 1 {
+			t.Fatalf("len(Changes()) = %d, want 1", len(update.Changes()))
+		}
+		if _, ok := update.Changes()[0].(UpdateEdited); !ok {
+			t.Errorf("Changes()[0] = %T, want UpdateEdited", update.Changes()[0])
+		}
+	})
+
+	t.Run("rejects unchanged content", func(t *testing.T) {
+		update := submittedUpdate(t)
+		if err := update.Edit(teamID, update.Content(), editor, editedAt); !errors.Is(err, ErrUpdateUnchanged) {
+			t.Errorf("Edit() error = %v, want ErrUpdateUnchanged", err)
+		}
+	})
+
+	t.Run("rejects another team's update", func(t *testing.T) {
+		update := submittedUpdate(t)
+		if err := update.Edit(otherTeam, fixed, editor, editedAt); !errors.Is(err, ErrUpdateNotFound) {
+			t.Errorf("Edit() error = %v, want ErrUpdateNotFound", err)
+		}
+	})
+
+	t.Run("rejects missing updates", func(t *testing.T) {
+		id, _ := NewUpdateID("update-123")
+		if err := RehydrateUpdate(id).Edit(teamID, fixed, editor, editedAt); !errors.Is(err, ErrUpdateNotFound) {
+			t.Errorf("Edit() error = %v, want ErrUpdateNotFound", err)
+		}
+	})
+}
+
+func TestUpdate_Delete(t *testing.T) {
+	teamID, _ := NewTeamID("team-123")
+	fixed, _ := NewUpdateContent("Working on feature Y")
+
+	update := submittedUpdate(t)
+	if err := update.Delete(teamID, SlackUserID{}, time.Now()); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if !update.IsDeleted() {
+		t.Error("IsDeleted() = false after Delete()")
+	}
+
+	// A deleted update can be neither edited nor deleted again
+	if err := update.Edit(teamID, fixed, SlackUserID{}, time.Now()); !errors.Is(err, ErrUpdateNotFound) {
+		t.Errorf("Edit() error = %v, want ErrUpdateNotFound", err)
+	}
+	if err := update.Delete(teamID, SlackUserID{}, time.Now()); !errors.Is(err, ErrUpdateNotFound) {
+		t.Errorf("Delete() error = %v, want ErrUpdateNotFound", err)
+	}
+
+	rehydrated := RehydrateUpdate(update.ID(), update.Changes()...)
+	if !rehydrated.IsDeleted() {
+		t.Error("rehydrated IsDeleted() = false")
+	}
+}
+
+func TestUpdate_Comment(t *testing.T) {
+	teamID, _ := NewTeamID("team-123")
+	otherTeam, _ := NewTeamID("team-456")
+	commentID, _ := NewCommentID("comment-1")
+	content, _ := NewUpdateContent("Nice, does that unblock the release?")
+	author, _ := NewAuthor("Bob")
+	slackUser, _ := NewSlackUserID("U456")
+
+	update := submittedUpdate(t)
+	if err := update.Comment(otherTeam, commentID, content, author, slackUser, SlackMessage{}, time.Now()); !errors.Is(err, ErrUpdateNotFound) {
+		t.Errorf("Comment() on another team's update error = %v, want ErrUpdateNotFound", err)
+	}
+
+	if err := update.Comment(teamID, commentID, content, author, slackUser, SlackMessage{}, time.Now()); err != nil {
+		t.Fatalf("Comment() error = %v", err)
+	}
+	changes := update.Changes()
+	commented, ok := changes[len(changes)-1].(UpdateCommented)
+	if !ok {
+		t.Fatalf("last change = %T, want UpdateCommented", changes[len(changes)-1])
+	}
+	if commented.CommentID != commentID || commented.TeamID != teamID || commented.Content != content {
+		t.Errorf("UpdateCommented = %+v", commented)
+	}
+
+	// Deleted updates take no more comments
+	if err := update.Delete(teamID, SlackUserID{}, time.Now()); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := update.Comment(teamID, commentID, content, author, slackUser, SlackMessage{}, time.Now()); !errors.Is(err, ErrUpdateNotFound) {
+		t.Errorf("Comment() on a deleted update error = %v, want ErrUpdateNotFound", err)
+	}
+}
+
+func TestNewStructuredUpdate(t *testing.T) {
+	id, _ := NewUpdateID("update-123")
+	teamID, _ := NewTeamID("team-123")
+	author, _ := NewAuthor("john.doe")
+	slackUser, _ := NewSlackUserID("U12345")
+	sections, _ := NewUpdateSections(strings.Repeat("d", 400), strings.Repeat("n", 400), "Waiting on API keys", HealthRed)
+
+	if _, err := NewStructuredUpdate(id, teamID, UpdateSections{}, author, slackUser, SlackMessage{}, time.Now()); err == nil {
+		t.Error("NewStructuredUpdate() without sections succeeded")
+	}
+
+	// The rendered content may be longer than free-text content allows
+	update, err := NewStructuredUpdate(id, teamID, sections, author, slackUser, SlackMessage{}, time.Now())
+	if err != nil {
+		t.Fatalf("NewStructuredUpdate() error = %v", err)
+	}
+	if update.Content().String() != sections.Text() {
+		t.Errorf("Content() = %q, want the rendered sections", update.Content())
+	}
+
+	rehydrated := RehydrateUpdate(id, update.Changes()...)
+	if rehydrated.Sections() != sections {
+		t.Errorf("rehydrated Sections() = %+v, want %+v", rehydrated.Sections(), sections)
+	}
+
+	// Editing the text would drop the sections
+	fixed, _ := NewUpdateContent("Shipped login, billing next")
+	if err := rehydrated.Edit(teamID, fixed, SlackUserID{}, time.Now()); !errors.Is(err, ErrUpdateStructured) {
+		t.Fatalf("Edit() error = %v, want ErrUpdateStructured", err)
+	}
+	if rehydrated.Sections() != sections || len(rehydrated.Changes()) != 0 {
+		t.Errorf("Sections() after Edit() = %+v with %d changes, want %+v unchanged", rehydrated.Sections(), len(rehydrated.Changes()), sections)
+	}
+}
@@ -125,6 +125,167 @@ func (u UpdateID) String() string {
 	return u.value
 }
 
+type CommentID struct {
+	value string
+}
+
+func NewCommentID(s string) (CommentID, error) {
+	if s == "" {
+		return CommentID{}, errors.New("comment ID cannot be empty")
+	}
+	return CommentID{value: s}, nil
+}
+
+func (c CommentID) String() string {
+	return c.value
+}
+
+// SlackMessage identifies the Slack message a status update was posted as.
+// The zero value means the update did not come from Slack.
+type SlackMessage struct {
+	channel   string
+	ts        string
+	threadTS  string // set when the message was posted in a thread
+	permalink string
+}
+
+func NewSlackMessage(channel, ts, threadTS, permalink string) (SlackMessage, error) {
+	if channel == "" {
+		return SlackMessage{}, errors.New("slack message channel cannot be empty")
+	}
+	if ts == "" {
+		return SlackMessage{}, errors.New("slack message timestamp cannot be empty")
+	}
+	return SlackMessage{channel: channel, ts: ts, threadTS: threadTS, permalink: permalink}, nil
+}
+
+func (m SlackMessage) Channel() string {
+	return m.channel
+}
+
+func (m SlackMessage) TS() string {
+	return m.ts
+}
+
+func (m SlackMessage) ThreadTS() string {
+	return m.threadTS
+}
+
+func (m SlackMessage) Permalink() string {
+	return m.permalink
+}
+
+func (m SlackMessage) IsZero() bool {
+	return m == SlackMessage{}
+}
+
+// Health is a traffic-light rating of how a team's work is going. The zero
+// value means no health was given.
+type Health struct {
+	value string
+}
+
+var (
+	HealthGreen = Health{value: "green"}
+	HealthAmber = Health{value: "amber"}
+	HealthRed   = Health{value: "red"}
+)
+
+func NewHealth(s string) (Health, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "green":
+		return HealthGreen, nil
+	case "amber":
+		return HealthAmber, nil
+	case "red":
+		return HealthRed, nil
+	}
+	return Health{}, fmt.Errorf("health must be green, amber or red, got %q", s)
+}
+
+func (h Health) String() string {
+	return h.value
+}
+
+func (h Health) IsZero() bool {
+	return h == Health{}
+}
+
+// maxSectionLength bounds each section of a structured update
+const maxSectionLength = 500
+
+// UpdateSections is a structured status update: what the team did, what it
+// will do next, what is blocking it and, optionally, its health. The zero
+// value means the update is free text.
+type UpdateSections struct {
+	done     string
+	next     string
+	blockers string
+	health   Health
+}
+
+func NewUpdateSections(done, next, blockers string, health Health) (UpdateSections, error) {
+	sections := UpdateSections{
+		done:     strings.TrimSpace(done),
+		next:     strings.TrimSpace(next),
+		blockers: strings.TrimSpace(blockers),
+		health:   health,
+	}
+	if sections.done == "" && sections.next == "" && sections.blockers == "" {
+		return UpdateSections{}, errors.New("structured update needs at least one of done, next or blockers")
+	}
+	for name, section := range map[string]string{"done": sections.done, "next": sections.next, "blockers": sections.blockers} {
+		if len(section) > maxSectionLength {
+			return UpdateSections{}, fmt.Errorf("%s must be %d characters or less", name, maxSectionLength)
+		}
+	}
+	return sections, nil
+}
+
+func (s UpdateSections) Done() string {
+	return s.done
+}
+
+func (s UpdateSections) Next() string {
+	return s.next
+}
+
+func (s UpdateSections) Blockers() string {
+	return s.blockers
+}
+
+func (s UpdateSections) Health() Health {
+	return s.health
+}
+
+func (s UpdateSections) IsZero() bool {
+	return s == UpdateSections{}
+}
+
+// Text renders the sections as the plain text content of the update
+func (s UpdateSections) Text() string {
+	var lines []string
+	if !s.health.IsZero() {
+		lines = append(lines, "Health: "+s.health.String())
+	}
+	for _, section := range []struct{ title, text string }{
+		{"Done", s.done},
+		{"Next", s.next},
+		{"Blockers", s.blockers},
+	} {
+		if section.text != "" {
+			lines = append(lines, section.title+": "+section.text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Content returns the rendered sections as the content of the update. It is
+// not held to UpdateContent's length limit; each section has its own.
+func (s UpdateSections) Content() UpdateContent {
+	return UpdateContent{value: s.Text()}
+}
+
 type ValidationError struct {
 	Field   string
 	Message string
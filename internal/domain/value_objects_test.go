@@ -157,3 +157,90 @@ func TestNewSlackUserID(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSlackMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		channel  string
+		ts       string
+		threadTS string
+		wantErr  bool
+	}{
+		{"valid message", "C123", "1700000000.000100", "", false},
+		{"threaded message", "C123", "1700000000.000200", "1700000000.000100", false},
+		{"empty channel", "", "1700000000.000100", "", true},
+		{"empty timestamp", "C123", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := NewSlackMessage(tt.channel, tt.ts, tt.threadTS, "")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewSlackMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (message.IsZero() || message.TS() != tt.ts || message.ThreadTS() != tt.threadTS) {
+				t.Errorf("NewSlackMessage() = %+v, want ts %s in thread %q", message, tt.ts, tt.threadTS)
+			}
+		})
+	}
+}
+
+func TestNewHealth(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Health
+		wantErr bool
+	}{
+		{"green", "green", HealthGreen, false},
+		{"amber with case and whitespace", " Amber ", HealthAmber, false},
+		{"red", "red", HealthRed, false},
+		{"empty string", "", Health{}, true},
+		{"unknown", "yellow", Health{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health, err := NewHealth(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewHealth() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if health != tt.want {
+				t.Errorf("NewHealth() = %v, want %v", health, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewUpdateSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		done     string
+		next     string
+		blockers string
+		health   Health
+		wantText string
+		wantErr  bool
+	}{
+		{"all sections", "Shipped login", "Start billing", "Waiting on API keys", HealthAmber,
+			"Health: amber\nDone: Shipped login\nNext: Start billing\nBlockers: Waiting on API keys", false},
+		{"only next", "", "  Start billing  ", "", Health{}, "Next: Start billing", false},
+		{"no sections", " ", "", "", HealthGreen, "", true},
+		{"section too long", strings.Repeat("a", 501), "", "", Health{}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sections, err := NewUpdateSections(tt.done, tt.next, tt.blockers, tt.health)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewUpdateSections() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && sections.Text() != tt.wantText {
+				t.Errorf("Text() = %q, want %q", sections.Text(), tt.wantText)
+			}
+		})
+	}
+}
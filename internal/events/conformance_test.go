@@ -0,0 +1,30 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/yourusername/status-app/internal/events"
+	"github.com/yourusername/status-app/internal/events/eventstoretest"
+	"github.com/yourusername/status-app/tests/testutil"
+)
+
+func TestMemoryStore_Conformance(t *testing.T) {
+	eventstoretest.Run(t, func(t *testing.T) events.Store {
+		store := events.NewMemoryStore()
+		t.Cleanup(func() { store.Close() })
+		return store
+	})
+}
+
+func TestPostgresStore_Conformance(t *testing.T) {
+	eventstoretest.Run(t, func(t *testing.T) events.Store {
+		testDB := testutil.SetupTestDB(t)
+		store, err := events.NewPostgresStore(testDB.ConnectionString())
+		testutil.AssertNoError(t, err, "NewPostgresStore")
+		t.Cleanup(func() {
+			store.Close()
+			testDB.Cleanup()
+		})
+		return store
+	})
+}
@@ -2,6 +2,8 @@ package events
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -14,23 +16,93 @@ type Event struct {
 	Timestamp   time.Time       `json:"timestamp"`
 	Metadata    json.RawMessage `json:"metadata,omitempty"`
 	Version     int             `json:"version"`
+	Position    int64           `json:"position"`
+
+	// SchemaVersion is the version of the Data payload's schema for this
+	// event type; older payloads are upcast on read (see Upcasters)
+	SchemaVersion int `json:"schema_version"`
 }
 
 // Event Types
 const (
 	StatusUpdateSubmitted = "status_update.submitted"
+	StatusUpdateEdited    = "status_update.edited"
+	StatusUpdateDeleted   = "status_update.deleted"
+	StatusUpdateCommented = "status_update.commented"
 	TeamRegistered        = "team.registered"
 	TeamUpdated           = "team.updated"
+	TeamHealthChanged     = "team.health_changed"
 )
 
-// StatusUpdateSubmittedData represents the data for a status update submission
+// Formats of a submitted status update
+const (
+	UpdateFormatText       = "text"
+	UpdateFormatStructured = "structured"
+)
+
+// StatusUpdateSubmittedData represents the data for a status update submission.
+// Structured updates carry Sections, with Content rendered from them.
+//
+// Schema version 2 added Format and Sections; version 1 payloads are upcast
+// to UpdateFormatText.
 type StatusUpdateSubmittedData struct {
+	UpdateID  string              `json:"update_id"`
+	TeamID    string              `json:"team_id"`
+	Content   string              `json:"content"`
+	Format    string              `json:"format"`
+	Sections  *UpdateSectionsData `json:"sections,omitempty"`
+	Author    string              `json:"author"`
+	SlackUser string              `json:"slack_user"`
+	Timestamp time.Time           `json:"timestamp"`
+
+	// The Slack message the update was posted as, if it came from Slack
+	SlackChannel   string `json:"slack_channel,omitempty"`
+	SlackTS        string `json:"slack_ts,omitempty"`
+	SlackThreadTS  string `json:"slack_thread_ts,omitempty"`
+	SlackPermalink string `json:"slack_permalink,omitempty"`
+}
+
+// UpdateSectionsData represents the sections of a structured status update
+type UpdateSectionsData struct {
+	Done     string `json:"done,omitempty"`
+	Next     string `json:"next,omitempty"`
+	Blockers string `json:"blockers,omitempty"`
+	Health   string `json:"health,omitempty"`
+}
+
+// StatusUpdateEditedData represents the data for a change to a status update's content
+type StatusUpdateEditedData struct {
+	UpdateID  string    `json:"update_id"`
+	TeamID    string    `json:"team_id"`
+	Content   string    `json:"content"`
+	EditedBy  string    `json:"edited_by,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// StatusUpdateDeletedData represents the data for a status update's removal
+type StatusUpdateDeletedData struct {
+	UpdateID  string    `json:"update_id"`
+	TeamID    string    `json:"team_id"`
+	DeletedBy string    `json:"deleted_by,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// StatusUpdateCommentedData represents the data for a comment on a status
+// update, such as a reply in the Slack thread under it
+type StatusUpdateCommentedData struct {
+	CommentID string    `json:"comment_id"`
 	UpdateID  string    `json:"update_id"`
 	TeamID    string    `json:"team_id"`
 	Content   string    `json:"content"`
 	Author    string    `json:"author"`
-	SlackUser string    `json:"slack_user"`
+	SlackUser string    `json:"slack_user,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
+
+	// The Slack message the comment was posted as, if it came from Slack
+	SlackChannel   string `json:"slack_channel,omitempty"`
+	SlackTS        string `json:"slack_ts,omitempty"`
+	SlackThreadTS  string `json:"slack_thread_ts,omitempty"`
+	SlackPermalink string `json:"slack_permalink,omitempty"`
 }
 
 // TeamRegisteredData represents the data for team registration
@@ -46,3 +118,89 @@ type TeamUpdatedData struct {
 	Name         string `json:"name"`
 	SlackChannel string `json:"slack_channel"`
 }
+
+// TeamHealthChangedData represents the data for a change of a team's health.
+// PreviousHealth is empty for the team's first rating.
+type TeamHealthChangedData struct {
+	TeamID         string    `json:"team_id"`
+	Health         string    `json:"health"`
+	PreviousHealth string    `json:"previous_health,omitempty"`
+	Reason         string    `json:"reason,omitempty"`
+	ChangedBy      string    `json:"changed_by,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+func (d StatusUpdateSubmittedData) Validate() error {
+	if d.UpdateID == "" || d.TeamID == "" {
+		return errors.New("update_id and team_id are required")
+	}
+	if d.Content == "" {
+		return errors.New("content is required")
+	}
+	switch d.Format {
+	case UpdateFormatText:
+		if d.Sections != nil {
+			return errors.New("text updates have no sections")
+		}
+	case UpdateFormatStructured:
+		if d.Sections == nil {
+			return errors.New("structured updates need sections")
+		}
+	default:
+		return fmt.Errorf("unknown format %q", d.Format)
+	}
+	return nil
+}
+
+func (d StatusUpdateEditedData) Validate() error {
+	if d.UpdateID == "" || d.TeamID == "" {
+		return errors.New("update_id and team_id are required")
+	}
+	if d.Content == "" {
+		return errors.New("content is required")
+	}
+	return nil
+}
+
+func (d StatusUpdateDeletedData) Validate() error {
+	if d.UpdateID == "" || d.TeamID == "" {
+		return errors.New("update_id and team_id are required")
+	}
+	return nil
+}
+
+func (d StatusUpdateCommentedData) Validate() error {
+	if d.CommentID == "" || d.UpdateID == "" || d.TeamID == "" {
+		return errors.New("comment_id, update_id and team_id are required")
+	}
+	if d.Content == "" {
+		return errors.New("content is required")
+	}
+	return nil
+}
+
+func (d TeamRegisteredData) Validate() error {
+	if d.TeamID == "" || d.Name == "" {
+		return errors.New("team_id and name are required")
+	}
+	return nil
+}
+
+func (d TeamUpdatedData) Validate() error {
+	if d.TeamID == "" || d.Name == "" {
+		return errors.New("team_id and name are required")
+	}
+	return nil
+}
+
+func (d TeamHealthChangedData) Validate() error {
+	if d.TeamID == "" {
+		return errors.New("team_id is required")
+	}
+	switch d.Health {
+	case "green", "amber", "red":
+	default:
+		return fmt.Errorf("health must be green, amber or red, got %q", d.Health)
+	}
+	return nil
+}
@@ -0,0 +1,459 @@
+// Package eventstoretest provides a conformance suite for events.Store
+// implementations. A new backend proves it is compatible by passing Run:
+//
+//	func TestMyStore_Conformance(t *testing.T) {
+//		eventstoretest.Run(t, func(t *testing.T) events.Store {
+//			return newMyStore(t)
+//		})
+//	}
+package eventstoretest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yourusername/status-app/internal/events"
+	"github.com/yourusername/status-app/tests/testutil"
+)
+
+// deliveryTimeout bounds how long a subscription may take to deliver an event
+const deliveryTimeout = 2 * time.Second
+
+// NewStore returns an empty store. It is called once per test and must
+// arrange for the store to be closed when the test ends.
+type NewStore func(t *testing.T) events.Store
+
+// Run checks the behavior every events.Store implementation must share
+func Run(t *testing.T, newStore NewStore) {
+	t.Run("Append", func(t *testing.T) { testAppend(t, newStore) })
+	t.Run("AppendExpected", func(t *testing.T) { testAppendExpected(t, newStore) })
+	t.Run("AppendBatch", func(t *testing.T) { testAppendBatch(t, newStore) })
+	t.Run("Ordering", func(t *testing.T) { testOrdering(t, newStore) })
+	t.Run("AggregateFiltering", func(t *testing.T) { testAggregateFiltering(t, newStore) })
+	t.Run("TypeFiltering", func(t *testing.T) { testTypeFiltering(t, newStore) })
+	t.Run("Pagination", func(t *testing.T) { testPagination(t, newStore) })
+	t.Run("MetadataRoundTrip", func(t *testing.T) { testMetadataRoundTrip(t, newStore) })
+	t.Run("CorrelationLookup", func(t *testing.T) { testCorrelationLookup(t, newStore) })
+	t.Run("SubscriptionDelivery", func(t *testing.T) { testSubscriptionDelivery(t, newStore) })
+	t.Run("SubscriptionFiltering", func(t *testing.T) { testSubscriptionFiltering(t, newStore) })
+	t.Run("SubscriptionCancellation", func(t *testing.T) { testSubscriptionCancellation(t, newStore) })
+}
+
+func testAppend(t *testing.T, newStore NewStore) {
+	ctx, store := context.Background(), newStore(t)
+
+	first := newEvent(t, events.TeamRegistered, "team-a")
+	second := newEvent(t, events.StatusUpdateSubmitted, "team-a")
+	other := newEvent(t, events.TeamRegistered, "team-b")
+	appendAll(t, store, first, second, other)
+
+	testutil.AssertEqual(t, first.Version, 1, "First version")
+	testutil.AssertEqual(t, second.Version, 2, "Second version")
+	testutil.AssertEqual(t, other.Version, 1, "Other aggregate version")
+	testutil.AssertEqual(t, first.Position, int64(1), "First position")
+	testutil.AssertEqual(t, second.Position, int64(2), "Second position")
+	testutil.AssertEqual(t, other.Position, int64(3), "Other position")
+
+	stored, err := store.GetByAggregateID(ctx, "team-a")
+	testutil.AssertNoError(t, err, "GetByAggregateID")
+	if len(stored) != 2 {
+		t.Fatalf("GetByAggregateID() returned %d events, want 2", len(stored))
+	}
+	assertSameEvent(t, stored[0], first)
+	assertSameEvent(t, stored[1], second)
+}
+
+func testAppendExpected(t *testing.T, newStore NewStore) {
+	ctx, store := context.Background(), newStore(t)
+
+	first := newEvent(t, events.TeamRegistered, "team-stale")
+	testutil.AssertNoError(t, store.AppendExpected(ctx, 0, first), "AppendExpected")
+
+	stale := newEvent(t, events.TeamRegistered, "team-stale")
+	if err := store.AppendExpected(ctx, 0, stale); !errors.Is(err, events.ErrConcurrencyConflict) {
+		t.Fatalf("AppendExpected() error = %v, want ErrConcurrencyConflict", err)
+	}
+
+	next := newEvent(t, events.TeamUpdated, "team-stale")
+	testutil.AssertNoError(t, store.AppendExpected(ctx, 1, next), "AppendExpected current version")
+	testutil.AssertEqual(t, next.Version, 2, "Next version")
+
+	stored, err := store.GetByAggregateID(ctx, "team-stale")
+	testutil.AssertNoError(t, err, "GetByAggregateID")
+	testutil.AssertEqual(t, len(stored), 2, "Event count")
+}
+
+func testAppendBatch(t *testing.T, newStore NewStore) {
+	ctx, store := context.Background(), newStore(t)
+
+	existing := newEvent(t, events.TeamRegistered, "team-batch")
+	appendAll(t, store, existing)
+
+	t.Run("stores contiguous versions and positions", func(t *testing.T) {
+		first := newEvent(t, events.StatusUpdateSubmitted, "team-batch")
+		second := newEvent(t, events.TeamUpdated, "team-batch")
+		other := newEvent(t, events.TeamRegistered, "team-batch-other")
+		testutil.AssertNoError(t, store.AppendBatch(ctx, first, second, other), "AppendBatch")
+
+		testutil.AssertEqual(t, first.Version, 2, "First version")
+		testutil.AssertEqual(t, second.Version, 3, "Second version")
+		testutil.AssertEqual(t, other.Version, 1, "Other aggregate version")
+		testutil.AssertEqual(t, second.Position, first.Position+1, "Second position")
+		testutil.AssertEqual(t, other.Position, first.Position+2, "Other position")
+	})
+
+	t.Run("stores nothing on conflict", func(t *testing.T) {
+		head, err := store.GetAll(ctx, "", 0, 100)
+		testutil.AssertNoError(t, err, "GetAll")
+
+		fresh := newEvent(t, events.TeamRegistered, "team-batch-fresh")
+		stale := newEvent(t, events.TeamRegistered, "team-batch")
+		stale.Version = 1 // team-batch is past version 1
+		if err := store.AppendBatch(ctx, fresh, stale); !errors.Is(err, events.ErrConcurrencyConflict) {
+			t.Fatalf("AppendBatch() error = %v, want ErrConcurrencyConflict", err)
+		}
+
+		after, err := store.GetAll(ctx, "", 0, 100)
+		testutil.AssertNoError(t, err, "GetAll")
+		testutil.AssertEqual(t, len(after), len(head), "Event count")
+
+		stored, err := store.GetByAggregateID(ctx, "team-batch-fresh")
+		testutil.AssertNoError(t, err, "GetByAggregateID")
+		testutil.AssertEqual(t, len(stored), 0, "Events of the rejected batch")
+	})
+
+	t.Run("accepts requested versions", func(t *testing.T) {
+		registered := newEvent(t, events.TeamRegistered, "team-batch-versions")
+		registered.Version = 1
+		submitted := newEvent(t, events.StatusUpdateSubmitted, "team-batch-versions")
+		submitted.Version = 2
+		testutil.AssertNoError(t, store.AppendBatch(ctx, registered, submitted), "AppendBatch")
+	})
+}
+
+func testOrdering(t *testing.T, newStore NewStore) {
+	ctx, store := context.Background(), newStore(t)
+
+	// Identical and decreasing timestamps must not affect order: position is
+	// assigned on append
+	now := time.Now()
+	var appended []*events.Event
+	for i := 0; i < 5; i++ {
+		event := newEvent(t, events.StatusUpdateSubmitted, "team-order")
+		event.Timestamp = now.Add(-time.Duration(i) * time.Minute)
+		appended = append(appended, event)
+	}
+	appendAll(t, store, appended...)
+
+	all, err := store.GetAll(ctx, "", 0, 100)
+	testutil.AssertNoError(t, err, "GetAll")
+	assertIDs(t, all, appended)
+
+	byAggregate, err := store.GetByAggregateID(ctx, "team-order")
+	testutil.AssertNoError(t, err, "GetByAggregateID")
+	assertIDs(t, byAggregate, appended)
+
+	for i, event := range all {
+		testutil.AssertEqual(t, event.Position, int64(i+1), "Position")
+	}
+}
+
+func testAggregateFiltering(t *testing.T, newStore NewStore) {
+	ctx, store := context.Background(), newStore(t)
+
+	a1 := newEvent(t, events.TeamRegistered, "team-a")
+	b1 := newEvent(t, events.TeamRegistered, "team-b")
+	a2 := newEvent(t, events.StatusUpdateSubmitted, "team-a")
+	appendAll(t, store, a1, b1, a2)
+
+	stored, err := store.GetByAggregateID(ctx, "team-a")
+	testutil.AssertNoError(t, err, "GetByAggregateID")
+	assertIDs(t, stored, []*events.Event{a1, a2})
+
+	after, err := store.GetByAggregateIDFrom(ctx, "team-a", a1.Version)
+	testutil.AssertNoError(t, err, "GetByAggregateIDFrom")
+	assertIDs(t, after, []*events.Event{a2})
+
+	missing, err := store.GetByAggregateID(ctx, "team-missing")
+	testutil.AssertNoError(t, err, "GetByAggregateID missing")
+	testutil.AssertEqual(t, len(missing), 0, "Missing aggregate event count")
+
+	version, err := store.AggregateVersion(ctx, "team-a")
+	testutil.AssertNoError(t, err, "AggregateVersion")
+	testutil.AssertEqual(t, version, a2.Version, "AggregateVersion")
+
+	version, err = store.AggregateVersion(ctx, "team-missing")
+	testutil.AssertNoError(t, err, "AggregateVersion missing")
+	testutil.AssertEqual(t, version, 0, "Missing aggregate version")
+}
+
+func testTypeFiltering(t *testing.T, newStore NewStore) {
+	ctx, store := context.Background(), newStore(t)
+
+	registered := newEvent(t, events.TeamRegistered, "team-a")
+	update := newEvent(t, events.StatusUpdateSubmitted, "team-a")
+	otherRegistered := newEvent(t, events.TeamRegistered, "team-b")
+	appendAll(t, store, registered, update, otherRegistered)
+
+	byType, err := store.GetAll(ctx, events.TeamRegistered, 0, 100)
+	testutil.AssertNoError(t, err, "GetAll")
+	assertIDs(t, byType, []*events.Event{registered, otherRegistered})
+
+	unknown, err := store.GetAll(ctx, "unknown.type", 0, 100)
+	testutil.AssertNoError(t, err, "GetAll unknown type")
+	testutil.AssertEqual(t, len(unknown), 0, "Unknown type event count")
+}
+
+func testPagination(t *testing.T, newStore NewStore) {
+	ctx, store := context.Background(), newStore(t)
+
+	var appended []*events.Event
+	for i := 0; i < 5; i++ {
+		appended = append(appended, newEvent(t, events.StatusUpdateSubmitted, "team-page"))
+	}
+	appendAll(t, store, appended...)
+
+	t.Run("GetAll offset and limit", func(t *testing.T) {
+		page, err := store.GetAll(ctx, "", 1, 2)
+		testutil.AssertNoError(t, err, "GetAll")
+		assertIDs(t, page, appended[1:3])
+
+		past, err := store.GetAll(ctx, "", 10, 2)
+		testutil.AssertNoError(t, err, "GetAll past end")
+		testutil.AssertEqual(t, len(past), 0, "Events past end")
+	})
+
+	t.Run("ReadFrom cursor", func(t *testing.T) {
+		var read []*events.Event
+		var position int64
+		for {
+			batch, err := store.ReadFrom(ctx, position, 2)
+			testutil.AssertNoError(t, err, "ReadFrom")
+			if len(batch) == 0 {
+				break
+			}
+			if len(batch) > 2 {
+				t.Fatalf("ReadFrom() returned %d events, want at most 2", len(batch))
+			}
+			read = append(read, batch...)
+			position = batch[len(batch)-1].Position
+		}
+		assertIDs(t, read, appended)
+	})
+}
+
+func testMetadataRoundTrip(t *testing.T, newStore NewStore) {
+	ctx, store := context.Background(), newStore(t)
+
+	withMetadata := newEvent(t, events.TeamRegistered, "team-meta")
+	withMetadata.Metadata = json.RawMessage(`{"correlation_id": "abc", "nested": {"count": 2}}`)
+	withMetadata.SchemaVersion = 3
+	withoutMetadata := newEvent(t, events.TeamUpdated, "team-meta")
+	appendAll(t, store, withMetadata, withoutMetadata)
+
+	// Events that do not set a schema version are stored at the initial one
+	testutil.AssertEqual(t, withoutMetadata.SchemaVersion, events.InitialSchemaVersion, "Default SchemaVersion")
+
+	stored, err := store.GetByAggregateID(ctx, "team-meta")
+	testutil.AssertNoError(t, err, "GetByAggregateID")
+	if len(stored) != 2 {
+		t.Fatalf("GetByAggregateID() returned %d events, want 2", len(stored))
+	}
+
+	assertSameEvent(t, stored[0], withMetadata)
+	testutil.AssertEqual(t, stored[0].SchemaVersion, withMetadata.SchemaVersion, "SchemaVersion")
+	if len(stored[1].Metadata) != 0 {
+		t.Errorf("Metadata = %s, want none", stored[1].Metadata)
+	}
+}
+
+func testCorrelationLookup(t *testing.T, newStore NewStore) {
+	ctx, store := context.Background(), newStore(t)
+
+	withCorrelation := func(event *events.Event, correlationID string) *events.Event {
+		metadata, err := events.Metadata{CorrelationID: correlationID, Actor: "tester"}.Marshal()
+		testutil.AssertNoError(t, err, "Marshal metadata")
+		event.Metadata = metadata
+		return event
+	}
+
+	first := withCorrelation(newEvent(t, events.TeamRegistered, "team-corr-a"), "corr-1")
+	unrelated := withCorrelation(newEvent(t, events.TeamRegistered, "team-corr-b"), "corr-2")
+	second := withCorrelation(newEvent(t, events.StatusUpdateSubmitted, "team-corr-a"), "corr-1")
+	untraced := newEvent(t, events.TeamUpdated, "team-corr-a")
+	appendAll(t, store, first, unrelated, second, untraced)
+
+	correlated, err := store.GetByCorrelationID(ctx, "corr-1")
+	testutil.AssertNoError(t, err, "GetByCorrelationID")
+	assertIDs(t, correlated, []*events.Event{first, second})
+
+	metadata, err := correlated[0].ParseMetadata()
+	testutil.AssertNoError(t, err, "ParseMetadata")
+	testutil.AssertEqual(t, metadata.Actor, "tester", "Actor")
+
+	missing, err := store.GetByCorrelationID(ctx, "corr-missing")
+	testutil.AssertNoError(t, err, "GetByCorrelationID missing")
+	testutil.AssertEqual(t, len(missing), 0, "Missing correlation event count")
+}
+
+func testSubscriptionDelivery(t *testing.T, newStore NewStore) {
+	store := newStore(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	before := newEvent(t, events.TeamRegistered, "team-sub")
+	history := newEvent(t, events.StatusUpdateSubmitted, "team-sub")
+	appendAll(t, store, before, history)
+
+	// Catch-up subscription replays history after the position, then goes live
+	fromCh, err := store.SubscribeFrom(ctx, before.Position, nil)
+	testutil.AssertNoError(t, err, "SubscribeFrom")
+
+	// Live subscription only sees events appended after subscribing
+	liveCh, err := store.Subscribe(ctx, nil)
+	testutil.AssertNoError(t, err, "Subscribe")
+
+	live := newEvent(t, events.StatusUpdateSubmitted, "team-sub")
+	appendAll(t, store, live)
+
+	expectEvents(t, fromCh, history, live)
+	expectEvents(t, liveCh, live)
+	expectNoEvent(t, fromCh)
+	expectNoEvent(t, liveCh)
+}
+
+func testSubscriptionFiltering(t *testing.T, newStore NewStore) {
+	store := newStore(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	eventsCh, err := store.SubscribeFrom(ctx, 0, []string{events.TeamRegistered, events.TeamUpdated})
+	testutil.AssertNoError(t, err, "SubscribeFrom")
+
+	registered := newEvent(t, events.TeamRegistered, "team-filter")
+	update := newEvent(t, events.StatusUpdateSubmitted, "team-filter")
+	renamed := newEvent(t, events.TeamUpdated, "team-filter")
+	appendAll(t, store, registered, update, renamed)
+
+	expectEvents(t, eventsCh, registered, renamed)
+	expectNoEvent(t, eventsCh)
+}
+
+func testSubscriptionCancellation(t *testing.T, newStore NewStore) {
+	store := newStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	eventsCh, err := store.Subscribe(ctx, nil)
+	testutil.AssertNoError(t, err, "Subscribe")
+
+	cancel()
+
+	deadline := time.After(deliveryTimeout)
+	for {
+		select {
+		case _, ok := <-eventsCh:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("subscription channel not closed after cancellation")
+		}
+	}
+}
+
+// newEvent returns an event with a unique ID and a small JSON payload
+func newEvent(t *testing.T, eventType, aggregateID string) *events.Event {
+	t.Helper()
+	return &events.Event{
+		ID:          uuid.New().String(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Data:        testutil.MustMarshalJSON(t, map[string]string{"aggregate_id": aggregateID}),
+		Timestamp:   time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+func appendAll(t *testing.T, store events.Store, toAppend ...*events.Event) {
+	t.Helper()
+	for _, event := range toAppend {
+		testutil.AssertNoError(t, store.Append(context.Background(), event), "Append "+event.Type)
+	}
+}
+
+// assertIDs checks got holds the events in want, in order
+func assertIDs(t *testing.T, got, want []*events.Event) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID {
+			t.Errorf("event %d ID = %s, want %s", i, got[i].ID, want[i].ID)
+		}
+	}
+}
+
+// assertSameEvent compares a loaded event with the appended one. JSON is
+// compared semantically since stores may normalize it.
+func assertSameEvent(t *testing.T, got, want *events.Event) {
+	t.Helper()
+	testutil.AssertEqual(t, got.ID, want.ID, "ID")
+	testutil.AssertEqual(t, got.Type, want.Type, "Type")
+	testutil.AssertEqual(t, got.AggregateID, want.AggregateID, "AggregateID")
+	testutil.AssertEqual(t, got.Version, want.Version, "Version")
+	testutil.AssertEqual(t, got.Position, want.Position, "Position")
+	if !got.Timestamp.Equal(want.Timestamp) {
+		t.Errorf("Timestamp = %v, want %v", got.Timestamp, want.Timestamp)
+	}
+	assertSameJSON(t, got.Data, want.Data, "Data")
+	assertSameJSON(t, got.Metadata, want.Metadata, "Metadata")
+}
+
+func assertSameJSON(t *testing.T, got, want json.RawMessage, field string) {
+	t.Helper()
+	if len(got) == 0 || len(want) == 0 {
+		if len(got) != len(want) {
+			t.Errorf("%s = %s, want %s", field, got, want)
+		}
+		return
+	}
+
+	var gotValue, wantValue interface{}
+	testutil.AssertNoError(t, json.Unmarshal(got, &gotValue), "unmarshal "+field)
+	testutil.AssertNoError(t, json.Unmarshal(want, &wantValue), "unmarshal expected "+field)
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("%s = %s, want %s", field, got, want)
+	}
+}
+
+func expectEvents(t *testing.T, ch <-chan *events.Event, want ...*events.Event) {
+	t.Helper()
+	for _, expected := range want {
+		select {
+		case received, ok := <-ch:
+			if !ok {
+				t.Fatalf("subscription closed before event %s", expected.ID)
+			}
+			testutil.AssertEqual(t, received.ID, expected.ID, "Event ID")
+		case <-time.After(deliveryTimeout):
+			t.Fatalf("timeout waiting for event %s", expected.ID)
+		}
+	}
+}
+
+func expectNoEvent(t *testing.T, ch <-chan *events.Event) {
+	t.Helper()
+	select {
+	case unexpected, ok := <-ch:
+		if ok {
+			t.Errorf("received unexpected event %s", unexpected.ID)
+		}
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,287 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrStoreClosed is returned by MemoryStore operations after Close
+var ErrStoreClosed = errors.New("event store closed")
+
+// MemoryStore is an in-memory Store for unit tests and local development.
+// It is safe for concurrent use and behaves like PostgresStore, including
+// optimistic concurrency, global positions and catch-up subscriptions.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	events   []*Event // in position order; events[i].Position == i+1
+	ids      map[string]struct{}
+	versions map[string]int
+
+	// appended is closed and replaced on every append to wake subscriptions
+	appended chan struct{}
+	closed   chan struct{}
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		ids:      make(map[string]struct{}),
+		versions: make(map[string]int),
+		appended: make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+}
+
+func (s *MemoryStore) Append(ctx context.Context, event *Event) error {
+	return s.append(ctx, []*Event{event}, []int{AnyVersion})
+}
+
+func (s *MemoryStore) AppendExpected(ctx context.Context, expectedVersion int, event *Event) error {
+	return s.append(ctx, []*Event{event}, []int{expectedVersion})
+}
+
+func (s *MemoryStore) AppendBatch(ctx context.Context, events ...*Event) error {
+	return s.append(ctx, events, batchExpectedVersions(events))
+}
+
+// append validates the whole batch before storing any of it, so a failed
+// batch leaves the log untouched
+func (s *MemoryStore) append(ctx context.Context, events []*Event, expectedVersions []int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isClosed() {
+		return ErrStoreClosed
+	}
+
+	aggregateVersions := make(map[string]int)
+	batchIDs := make(map[string]struct{}, len(events))
+	versions := make([]int, len(events))
+
+	for i, event := range events {
+		_, stored := s.ids[event.ID]
+		_, batched := batchIDs[event.ID]
+		if stored || batched {
+			eventStoreErrors.WithLabelValues("append").Inc()
+			return fmt.Errorf("failed to append event: duplicate event ID %s", event.ID)
+		}
+		batchIDs[event.ID] = struct{}{}
+
+		currentVersion, ok := aggregateVersions[event.AggregateID]
+		if !ok {
+			currentVersion = s.versions[event.AggregateID]
+		}
+		if expectedVersions[i] != AnyVersion && currentVersion != expectedVersions[i] {
+			eventStoreErrors.WithLabelValues("concurrency_conflict").Inc()
+			return fmt.Errorf("%w: aggregate %s is at version %d, expected %d",
+				ErrConcurrencyConflict, event.AggregateID, currentVersion, expectedVersions[i])
+		}
+
+		versions[i] = currentVersion + 1
+		aggregateVersions[event.AggregateID] = versions[i]
+	}
+
+	for i, event := range events {
+		event.Version = versions[i]
+		event.Position = int64(len(s.events)) + 1
+		event.SchemaVersion = storedSchemaVersion(event)
+
+		s.events = append(s.events, copyEvent(event))
+		s.ids[event.ID] = struct{}{}
+		s.versions[event.AggregateID] = event.Version
+
+		eventsStoredTotal.WithLabelValues(event.Type).Inc()
+		eventsStoredBytes.Add(float64(len(event.Data)))
+	}
+
+	close(s.appended)
+	s.appended = make(chan struct{})
+
+	return nil
+}
+
+func (s *MemoryStore) GetByAggregateID(ctx context.Context, aggregateID string) ([]*Event, error) {
+	return s.GetByAggregateIDFrom(ctx, aggregateID, 0)
+}
+
+func (s *MemoryStore) GetByAggregateIDFrom(ctx context.Context, aggregateID string, version int) ([]*Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Versions increase with position, so position order is version order
+	var result []*Event
+	for _, event := range s.events {
+		if event.AggregateID == aggregateID && event.Version > version {
+			result = append(result, s.load(event))
+		}
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) AggregateVersion(ctx context.Context, aggregateID string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.versions[aggregateID], nil
+}
+
+func (s *MemoryStore) GetByCorrelationID(ctx context.Context, correlationID string) ([]*Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*Event
+	for _, event := range s.events {
+		// Unparseable metadata cannot match, as in PostgresStore
+		metadata, err := event.ParseMetadata()
+		if err == nil && metadata.CorrelationID == correlationID {
+			result = append(result, s.load(event))
+		}
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) GetAll(ctx context.Context, eventType string, offset, limit int) ([]*Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*Event
+	for _, event := range s.events {
+		if eventType != "" && event.Type != eventType {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if len(result) >= limit {
+			break
+		}
+		result = append(result, s.load(event))
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) ReadFrom(ctx context.Context, position int64, batchSize int) ([]*Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.readFrom(position, batchSize), nil
+}
+
+// readFrom returns up to batchSize events after position; s.mu must be held
+func (s *MemoryStore) readFrom(position int64, batchSize int) []*Event {
+	if position < 0 {
+		position = 0
+	}
+	var result []*Event
+	for i := position; i < int64(len(s.events)) && len(result) < batchSize; i++ {
+		result = append(result, s.load(s.events[i]))
+	}
+	return result
+}
+
+func (s *MemoryStore) Subscribe(ctx context.Context, eventTypes []string) (<-chan *Event, error) {
+	s.mu.RLock()
+	head := int64(len(s.events))
+	s.mu.RUnlock()
+
+	return s.SubscribeFrom(ctx, head, eventTypes)
+}
+
+func (s *MemoryStore) SubscribeFrom(ctx context.Context, position int64, eventTypes []string) (<-chan *Event, error) {
+	if s.isClosed() {
+		return nil, ErrStoreClosed
+	}
+
+	ch := make(chan *Event, 10)
+
+	go func() {
+		defer close(ch)
+
+		for {
+			// Take the wake-up channel together with the batch so an append
+			// between reading and waiting is never missed
+			s.mu.RLock()
+			batch := s.readFrom(position, subscriptionBatchSize)
+			appended := s.appended
+			s.mu.RUnlock()
+
+			for _, event := range batch {
+				position = event.Position
+				if !matchesEventTypes(event, eventTypes) {
+					continue
+				}
+
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+					return
+				case <-s.closed:
+					return
+				}
+			}
+
+			if len(batch) == subscriptionBatchSize {
+				continue
+			}
+
+			select {
+			case <-appended:
+			case <-ctx.Done():
+				return
+			case <-s.closed:
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// Close ends all subscriptions; further appends fail with ErrStoreClosed
+func (s *MemoryStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isClosed() {
+		close(s.closed)
+	}
+	return nil
+}
+
+func (s *MemoryStore) isClosed() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// load returns a copy of a stored event so callers cannot modify the log
+func (s *MemoryStore) load(event *Event) *Event {
+	eventsLoadedTotal.WithLabelValues(event.Type).Inc()
+	return copyEvent(event)
+}
+
+func copyEvent(event *Event) *Event {
+	c := *event
+	if event.Data != nil {
+		c.Data = append([]byte(nil), event.Data...)
+	}
+	if len(event.Metadata) > 0 {
+		c.Metadata = append([]byte(nil), event.Metadata...)
+	} else {
+		// PostgresStore stores empty metadata as NULL
+		c.Metadata = nil
+	}
+	return &c
+}
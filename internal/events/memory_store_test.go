@@ -0,0 +1,33 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/status-app/tests/testutil"
+)
+
+func TestMemoryStore_Close(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	eventsCh, err := store.Subscribe(ctx, nil)
+	testutil.AssertNoError(t, err, "Subscribe")
+	testutil.AssertNoError(t, store.Close(), "Close")
+
+	select {
+	case _, ok := <-eventsCh:
+		if ok {
+			t.Error("received event after Close")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription channel not closed after Close")
+	}
+
+	event := newTeamRegisteredEvent(t, "team-closed", "Engineering", "#engineering", "", time.Now())
+	if err := store.Append(ctx, event); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("Append() after Close error = %v, want ErrStoreClosed", err)
+	}
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Metadata is the standard envelope stored in Event.Metadata, tracing an event
+// back to the request that caused it
+type Metadata struct {
+	// CorrelationID is shared by everything that happened because of one
+	// user action, across services
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// CausationID identifies the message (request, command or event) that
+	// directly caused this event
+	CausationID string `json:"causation_id,omitempty"`
+	// Actor is who acted: an API key name or a Slack user
+	Actor string `json:"actor,omitempty"`
+	// Source is the service the action came from, e.g. "slackbot"
+	Source string `json:"source,omitempty"`
+	// RequestID identifies the HTTP request that appended the event
+	RequestID string `json:"request_id,omitempty"`
+}
+
+type metadataKey struct{}
+
+// WithMetadata returns a context carrying metadata for the events appended under it
+func WithMetadata(ctx context.Context, metadata Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// MetadataFromContext returns the metadata carried by ctx, if any
+func MetadataFromContext(ctx context.Context) Metadata {
+	metadata, _ := ctx.Value(metadataKey{}).(Metadata)
+	return metadata
+}
+
+// IsZero reports whether no metadata field is set
+func (m Metadata) IsZero() bool {
+	return m == Metadata{}
+}
+
+// Marshal encodes metadata for Event.Metadata, returning nil when empty
+func (m Metadata) Marshal() (json.RawMessage, error) {
+	if m.IsZero() {
+		return nil, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event metadata: %w", err)
+	}
+	return data, nil
+}
+
+// String formats metadata as key=value pairs for log lines
+func (m Metadata) String() string {
+	var parts []string
+	for _, field := range []struct{ key, value string }{
+		{"correlation_id", m.CorrelationID},
+		{"causation_id", m.CausationID},
+		{"actor", m.Actor},
+		{"source", m.Source},
+		{"request_id", m.RequestID},
+	} {
+		if field.value != "" {
+			parts = append(parts, field.key+"="+field.value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseMetadata decodes the event's metadata envelope. Events without metadata
+// return the zero Metadata.
+func (e *Event) ParseMetadata() (Metadata, error) {
+	var metadata Metadata
+	if len(e.Metadata) == 0 {
+		return metadata, nil
+	}
+	if err := json.Unmarshal(e.Metadata, &metadata); err != nil {
+		return metadata, fmt.Errorf("failed to parse metadata of event %s: %w", e.ID, err)
+	}
+	return metadata, nil
+}
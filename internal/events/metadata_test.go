@@ -0,0 +1,53 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/status-app/tests/testutil"
+)
+
+func TestMetadata_Context(t *testing.T) {
+	if got := MetadataFromContext(context.Background()); !got.IsZero() {
+		t.Errorf("MetadataFromContext() = %+v, want zero", got)
+	}
+
+	want := Metadata{CorrelationID: "corr-1", Actor: "slack:U123"}
+	got := MetadataFromContext(WithMetadata(context.Background(), want))
+	testutil.AssertEqual(t, got, want, "Metadata")
+}
+
+func TestMetadata_MarshalParse(t *testing.T) {
+	t.Run("empty metadata is stored as none", func(t *testing.T) {
+		data, err := Metadata{}.Marshal()
+		testutil.AssertNoError(t, err, "Marshal")
+		if data != nil {
+			t.Errorf("Marshal() = %s, want nil", data)
+		}
+
+		parsed, err := (&Event{}).ParseMetadata()
+		testutil.AssertNoError(t, err, "ParseMetadata")
+		testutil.AssertEqual(t, parsed.IsZero(), true, "IsZero")
+	})
+
+	t.Run("round trips every field", func(t *testing.T) {
+		want := Metadata{
+			CorrelationID: "corr-1",
+			CausationID:   "cause-1",
+			Actor:         "api-key",
+			Source:        "api",
+			RequestID:     "req-1",
+		}
+		data, err := want.Marshal()
+		testutil.AssertNoError(t, err, "Marshal")
+
+		got, err := (&Event{Metadata: data}).ParseMetadata()
+		testutil.AssertNoError(t, err, "ParseMetadata")
+		testutil.AssertEqual(t, got, want, "Metadata")
+	})
+
+	t.Run("formats for logs", func(t *testing.T) {
+		got := Metadata{CorrelationID: "corr-1", Actor: "slack:U123"}.String()
+		testutil.AssertEqual(t, got, "correlation_id=corr-1 actor=slack:U123", "String")
+	})
+}
@@ -36,6 +36,16 @@ var (
 		[]string{"event_type"},
 	)
 
+	eventsUnknownTypeTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "status_app",
+			Subsystem: "events",
+			Name:      "unknown_type_total",
+			Help:      "Total number of events seen with a type missing from the registry",
+		},
+		[]string{"event_type"},
+	)
+
 	eventStoreErrors = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "status_app",
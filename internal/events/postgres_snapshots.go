@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// PostgresSnapshotStore keeps snapshots in the event store database
+type PostgresSnapshotStore struct {
+	db *sql.DB
+}
+
+// Snapshots returns a SnapshotStore sharing the event store's connection pool
+func (s *PostgresStore) Snapshots() *PostgresSnapshotStore {
+	return &PostgresSnapshotStore{db: s.db}
+}
+
+func (s *PostgresSnapshotStore) Load(ctx context.Context, aggregateID string) (*Snapshot, error) {
+	query := `
+		SELECT aggregate_id, aggregate_type, version, schema_version, state, created_at
+		FROM snapshots
+		WHERE aggregate_id = $1
+	`
+	var snapshot Snapshot
+	var state []byte
+	err := s.db.QueryRowContext(ctx, query, aggregateID).Scan(
+		&snapshot.AggregateID,
+		&snapshot.AggregateType,
+		&snapshot.Version,
+		&snapshot.SchemaVersion,
+		&state,
+		&snapshot.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		eventStoreErrors.WithLabelValues("load_snapshot").Inc()
+		return nil, fmt.Errorf("failed to load snapshot of %s: %w", aggregateID, err)
+	}
+	snapshot.State = state
+	return &snapshot, nil
+}
+
+func (s *PostgresSnapshotStore) Save(ctx context.Context, snapshot *Snapshot) error {
+	query := `
+		INSERT INTO snapshots (aggregate_id, aggregate_type, version, schema_version, state, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (aggregate_id) DO UPDATE SET
+			aggregate_type = EXCLUDED.aggregate_type,
+			version = EXCLUDED.version,
+			schema_version = EXCLUDED.schema_version,
+			state = EXCLUDED.state,
+			created_at = EXCLUDED.created_at
+		WHERE snapshots.version < EXCLUDED.version
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		snapshot.AggregateID,
+		snapshot.AggregateType,
+		snapshot.Version,
+		snapshot.SchemaVersion,
+		string(snapshot.State),
+		snapshot.CreatedAt,
+	)
+	if err != nil {
+		eventStoreErrors.WithLabelValues("save_snapshot").Inc()
+		return fmt.Errorf("failed to save snapshot of %s: %w", snapshot.AggregateID, err)
+	}
+	return nil
+}
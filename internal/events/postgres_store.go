@@ -4,12 +4,35 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/lib/pq"
-	_ "github.com/lib/pq"
+)
+
+const (
+	// aggregateVersionConstraint guarantees a single event per aggregate version
+	aggregateVersionConstraint = "events_aggregate_version_unique"
+
+	// positionLockKey is the advisory lock serializing appends so global
+	// positions are assigned gap-free and in commit order
+	positionLockKey = 7_340_001
+
+	// subscriptionBatchSize is the number of events read per catch-up query
+	subscriptionBatchSize = 100
+
+	// notifyChannel is the LISTEN/NOTIFY channel announcing appended events
+	notifyChannel = "events"
+
+	// subscriptionSweepInterval is how often subscriptions read the log without
+	// a notification, re-delivering anything a lost notification left behind
+	subscriptionSweepInterval = 30 * time.Second
+
+	// eventColumns is the column list scanned by scanEvent
+	eventColumns = "id, type, aggregate_id, data, timestamp, metadata, version, position, schema_version"
 )
 
 type PostgresStore struct {
@@ -34,9 +57,105 @@ func NewPostgresStore(connStr string) (*PostgresStore, error) {
 }
 
 func (s *PostgresStore) Append(ctx context.Context, event *Event) error {
+	return s.append(ctx, []*Event{event}, []int{AnyVersion})
+}
+
+func (s *PostgresStore) AppendExpected(ctx context.Context, expectedVersion int, event *Event) error {
+	return s.append(ctx, []*Event{event}, []int{expectedVersion})
+}
+
+func (s *PostgresStore) AppendBatch(ctx context.Context, events ...*Event) error {
+	return s.append(ctx, events, batchExpectedVersions(events))
+}
+
+// append stores events in one transaction, each only if its aggregate is at the
+// matching expectedVersions entry (or AnyVersion)
+func (s *PostgresStore) append(ctx context.Context, events []*Event, expectedVersions []int) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		eventStoreErrors.WithLabelValues("append").Inc()
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Serialize appends: readers paging by position must never observe
+	// position N+1 committed before position N
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, positionLockKey); err != nil {
+		eventStoreErrors.WithLabelValues("append").Inc()
+		return fmt.Errorf("failed to acquire append lock: %w", err)
+	}
+
+	var position int64
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(position), 0) FROM events`).Scan(&position); err != nil {
+		eventStoreErrors.WithLabelValues("append").Inc()
+		return fmt.Errorf("failed to read head position: %w", err)
+	}
+
+	// Versions of aggregates touched by this batch, including its own events
+	aggregateVersions := make(map[string]int)
+	versions := make([]int, len(events))
+	positions := make([]int64, len(events))
+
+	for i, event := range events {
+		currentVersion, ok := aggregateVersions[event.AggregateID]
+		if !ok {
+			versionQuery := `SELECT COALESCE(MAX(version), 0) FROM events WHERE aggregate_id = $1`
+			if err := tx.QueryRowContext(ctx, versionQuery, event.AggregateID).Scan(&currentVersion); err != nil {
+				eventStoreErrors.WithLabelValues("append").Inc()
+				return fmt.Errorf("failed to read aggregate version: %w", err)
+			}
+		}
+
+		if expectedVersions[i] != AnyVersion && currentVersion != expectedVersions[i] {
+			eventStoreErrors.WithLabelValues("concurrency_conflict").Inc()
+			return fmt.Errorf("%w: aggregate %s is at version %d, expected %d",
+				ErrConcurrencyConflict, event.AggregateID, currentVersion, expectedVersions[i])
+		}
+
+		position++
+		versions[i] = currentVersion + 1
+		positions[i] = position
+		aggregateVersions[event.AggregateID] = versions[i]
+
+		if err := s.insertEvent(ctx, tx, event, versions[i], positions[i]); err != nil {
+			return err
+		}
+	}
+
+	// Notify listeners within the transaction: PostgreSQL delivers the
+	// notification on commit, so an event is never stored without one
+	if _, err := tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, notifyChannel, strconv.FormatInt(position, 10)); err != nil {
+		eventStoreErrors.WithLabelValues("append").Inc()
+		return fmt.Errorf("failed to notify listeners: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		eventStoreErrors.WithLabelValues("append").Inc()
+		return fmt.Errorf("failed to commit event: %w", err)
+	}
+
+	for i, event := range events {
+		event.Version = versions[i]
+		event.Position = positions[i]
+		event.SchemaVersion = storedSchemaVersion(event)
+
+		// Record metrics
+		eventsStoredTotal.WithLabelValues(event.Type).Inc()
+		eventsStoredBytes.Add(float64(len(event.Data)))
+	}
+
+	return nil
+}
+
+// insertEvent writes a single event row within tx
+func (s *PostgresStore) insertEvent(ctx context.Context, tx *sql.Tx, event *Event, version int, position int64) error {
 	query := `
-		INSERT INTO events (id, type, aggregate_id, data, timestamp, metadata, version)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO events (id, type, aggregate_id, data, timestamp, metadata, version, position, schema_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 	
 	// Handle nil metadata - PostgreSQL expects NULL, not an empty json.RawMessage
@@ -47,41 +166,69 @@ func (s *PostgresStore) Append(ctx context.Context, event *Event) error {
 		metadata = event.Metadata
 	}
 	
-	_, err := s.db.ExecContext(ctx, query,
+	_, err := tx.ExecContext(ctx, query,
 		event.ID,
 		event.Type,
 		event.AggregateID,
 		event.Data,
 		event.Timestamp,
 		metadata,
-		event.Version,
+		version,
+		position,
+		storedSchemaVersion(event),
 	)
 	if err != nil {
+		// A concurrent writer claimed the same (aggregate_id, version) first
+		if isUniqueViolation(err, aggregateVersionConstraint) {
+			eventStoreErrors.WithLabelValues("concurrency_conflict").Inc()
+			return fmt.Errorf("%w: aggregate %s was modified concurrently", ErrConcurrencyConflict, event.AggregateID)
+		}
 		eventStoreErrors.WithLabelValues("append").Inc()
 		return fmt.Errorf("failed to append event: %w", err)
 	}
+	return nil
+}
 
-	// Record metrics
-	eventsStoredTotal.WithLabelValues(event.Type).Inc()
-	eventsStoredBytes.Add(float64(len(event.Data)))
+func (s *PostgresStore) GetByAggregateID(ctx context.Context, aggregateID string) ([]*Event, error) {
+	return s.GetByAggregateIDFrom(ctx, aggregateID, 0)
+}
 
-	// Notify listeners (PostgreSQL NOTIFY)
-	notifyQuery := fmt.Sprintf("NOTIFY events, '%s'", event.ID)
-	if _, err := s.db.ExecContext(ctx, notifyQuery); err != nil {
-		log.Printf("Warning: failed to notify listeners: %v", err)
+func (s *PostgresStore) GetByAggregateIDFrom(ctx context.Context, aggregateID string, version int) ([]*Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE aggregate_id = $1 AND version > $2
+		ORDER BY version ASC
+	`
+	rows, err := s.db.QueryContext(ctx, query, aggregateID, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
+	return s.scanEvents(rows)
 }
 
-func (s *PostgresStore) GetByAggregateID(ctx context.Context, aggregateID string) ([]*Event, error) {
+func (s *PostgresStore) AggregateVersion(ctx context.Context, aggregateID string) (int, error) {
+	var version int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM events WHERE aggregate_id = $1`,
+		aggregateID,
+	).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read version of %s: %w", aggregateID, err)
+	}
+	return version, nil
+}
+
+func (s *PostgresStore) GetByCorrelationID(ctx context.Context, correlationID string) ([]*Event, error) {
 	query := `
-		SELECT id, type, aggregate_id, data, timestamp, metadata, version
+		SELECT ` + eventColumns + `
 		FROM events
-		WHERE aggregate_id = $1
-		ORDER BY timestamp ASC
+		WHERE metadata->>'correlation_id' = $1
+		ORDER BY position ASC
 	`
-	rows, err := s.db.QueryContext(ctx, query, aggregateID)
+	rows, err := s.db.QueryContext(ctx, query, correlationID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query events: %w", err)
 	}
@@ -96,18 +243,18 @@ func (s *PostgresStore) GetAll(ctx context.Context, eventType string, offset, li
 
 	if eventType != "" {
 		query = `
-			SELECT id, type, aggregate_id, data, timestamp, metadata, version
+			SELECT ` + eventColumns + `
 			FROM events
 			WHERE type = $1
-			ORDER BY timestamp ASC
+			ORDER BY position ASC
 			LIMIT $2 OFFSET $3
 		`
 		args = []interface{}{eventType, limit, offset}
 	} else {
 		query = `
-			SELECT id, type, aggregate_id, data, timestamp, metadata, version
+			SELECT ` + eventColumns + `
 			FROM events
-			ORDER BY timestamp ASC
+			ORDER BY position ASC
 			LIMIT $1 OFFSET $2
 		`
 		args = []interface{}{limit, offset}
@@ -122,7 +269,34 @@ func (s *PostgresStore) GetAll(ctx context.Context, eventType string, offset, li
 	return s.scanEvents(rows)
 }
 
+func (s *PostgresStore) ReadFrom(ctx context.Context, position int64, batchSize int) ([]*Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE position > $1
+		ORDER BY position ASC
+		LIMIT $2
+	`
+	rows, err := s.db.QueryContext(ctx, query, position, batchSize)
+	if err != nil {
+		eventStoreErrors.WithLabelValues("read_from").Inc()
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanEvents(rows)
+}
+
 func (s *PostgresStore) Subscribe(ctx context.Context, eventTypes []string) (<-chan *Event, error) {
+	var head int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(position), 0) FROM events`).Scan(&head); err != nil {
+		return nil, fmt.Errorf("failed to read head position: %w", err)
+	}
+
+	return s.SubscribeFrom(ctx, head, eventTypes)
+}
+
+func (s *PostgresStore) SubscribeFrom(ctx context.Context, position int64, eventTypes []string) (<-chan *Event, error) {
 	// Create a new connection for listening (LISTEN requires its own connection)
 	listener := pq.NewListener(
 		s.connStr,
@@ -135,7 +309,10 @@ func (s *PostgresStore) Subscribe(ctx context.Context, eventTypes []string) (<-c
 		},
 	)
 
-	if err := listener.Listen("events"); err != nil {
+	// Listen before the first read: anything appended while history is being
+	// replayed produces a notification that triggers another read
+	if err := listener.Listen(notifyChannel); err != nil {
+		listener.Close()
 		return nil, fmt.Errorf("failed to listen on events channel: %w", err)
 	}
 
@@ -145,59 +322,80 @@ func (s *PostgresStore) Subscribe(ctx context.Context, eventTypes []string) (<-c
 		defer listener.Close()
 		defer close(ch)
 
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case notification := <-listener.Notify:
-				if notification == nil {
-					continue
-				}
+		sweep := time.NewTicker(subscriptionSweepInterval)
+		defer sweep.Stop()
 
-				// Fetch the event by ID from the notification payload
-				eventID := notification.Extra
-				event, err := s.getEventByID(ctx, eventID)
+		for {
+			// Deliver everything after the last delivered position. Notifications
+			// only wake us up, so events are never skipped or delivered twice
+			for {
+				batch, err := s.ReadFrom(ctx, position, subscriptionBatchSize)
 				if err != nil {
-					log.Printf("failed to fetch event %s: %v", eventID, err)
-					continue
+					if ctx.Err() != nil {
+						return
+					}
+					log.Printf("failed to read events after position %d: %v", position, err)
+					break
 				}
 
-				// Filter by event type if specified
-				if len(eventTypes) > 0 {
-					match := false
-					for _, et := range eventTypes {
-						if event.Type == et {
-							match = true
-							break
-						}
-					}
-					if !match {
+				for _, event := range batch {
+					position = event.Position
+					if !matchesEventTypes(event, eventTypes) {
 						continue
 					}
+
+					select {
+					case ch <- event:
+					case <-ctx.Done():
+						return
+					}
 				}
 
-				select {
-				case ch <- event:
-				case <-ctx.Done():
-					return
+				if len(batch) < subscriptionBatchSize {
+					break
 				}
 			}
+
+			// A nil notification means the listener reconnected and may have
+			// missed notifications; reading from position covers that too, as
+			// does the periodic sweep for notifications lost without a reconnect
+			select {
+			case <-ctx.Done():
+				return
+			case <-listener.Notify:
+			case <-sweep.C:
+			}
 		}
 	}()
 
 	return ch, nil
 }
 
-func (s *PostgresStore) getEventByID(ctx context.Context, eventID string) (*Event, error) {
-	query := `
-		SELECT id, type, aggregate_id, data, timestamp, metadata, version
-		FROM events
-		WHERE id = $1
-	`
+// matchesEventTypes reports whether event is one of eventTypes (all when empty)
+func matchesEventTypes(event *Event, eventTypes []string) bool {
+	if len(eventTypes) == 0 {
+		return true
+	}
+	for _, et := range eventTypes {
+		if event.Type == et {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
+
+// scanEvent scans an Event from a row scanner selecting eventColumns
+func scanEvent(scanner interface {
+	Scan(...interface{}) error
+}) (*Event, error) {
 	var event Event
 	var metadata sql.NullString
 
-	err := s.db.QueryRowContext(ctx, query, eventID).Scan(
+	err := scanner.Scan(
 		&event.ID,
 		&event.Type,
 		&event.AggregateID,
@@ -205,9 +403,11 @@ func (s *PostgresStore) getEventByID(ctx context.Context, eventID string) (*Even
 		&event.Timestamp,
 		&metadata,
 		&event.Version,
+		&event.Position,
+		&event.SchemaVersion,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query event: %w", err)
+		return nil, err
 	}
 
 	if metadata.Valid {
@@ -217,40 +417,30 @@ func (s *PostgresStore) getEventByID(ctx context.Context, eventID string) (*Even
 	return &event, nil
 }
 
-func (s *PostgresStore) Close() error {
-	return s.db.Close()
-}
-
 func (s *PostgresStore) scanEvents(rows *sql.Rows) ([]*Event, error) {
 	var events []*Event
 
 	for rows.Next() {
-		var event Event
-		var metadata sql.NullString
-
-		err := rows.Scan(
-			&event.ID,
-			&event.Type,
-			&event.AggregateID,
-			&event.Data,
-			&event.Timestamp,
-			&metadata,
-			&event.Version,
-		)
+		event, err := scanEvent(rows)
 		if err != nil {
 			eventStoreErrors.WithLabelValues("scan").Inc()
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
 
-		if metadata.Valid {
-			event.Metadata = json.RawMessage(metadata.String)
-		}
-
 		// Record metric for loaded event
 		eventsLoadedTotal.WithLabelValues(event.Type).Inc()
 
-		events = append(events, &event)
+		events = append(events, event)
 	}
 
 	return events, rows.Err()
 }
+
+// isUniqueViolation reports whether err is a unique violation of the named constraint
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
@@ -3,9 +3,12 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/yourusername/status-app/tests/testutil"
 )
 
@@ -129,6 +132,79 @@ func TestPostgresStore_Append(t *testing.T) {
 	})
 }
 
+func TestPostgresStore_AppendExpected(t *testing.T) {
+	ctx, store, _ := setupEventStore(t)
+
+	t.Run("assigns contiguous versions", func(t *testing.T) {
+		first := newTeamRegisteredEvent(t, "team-versions", "Engineering", "#engineering", "", time.Now())
+		testutil.AssertNoError(t, store.AppendExpected(ctx, 0, first), "AppendExpected first")
+		testutil.AssertEqual(t, first.Version, 1, "First version")
+
+		second := newStatusUpdateEvent(t, "team-versions", "Update", "Alice", "alice", time.Now())
+		testutil.AssertNoError(t, store.Append(ctx, second), "Append second")
+		testutil.AssertEqual(t, second.Version, 2, "Second version")
+	})
+
+	t.Run("rejects stale expected version", func(t *testing.T) {
+		first := newTeamRegisteredEvent(t, "team-stale", "Engineering", "#engineering", "", time.Now())
+		testutil.AssertNoError(t, store.AppendExpected(ctx, 0, first), "AppendExpected first")
+
+		duplicate := newTeamRegisteredEvent(t, "team-stale", "Engineering", "#engineering", "", time.Now())
+		err := store.AppendExpected(ctx, 0, duplicate)
+		if !errors.Is(err, ErrConcurrencyConflict) {
+			t.Fatalf("AppendExpected() error = %v, want ErrConcurrencyConflict", err)
+		}
+
+		events, err := store.GetByAggregateID(ctx, "team-stale")
+		testutil.AssertNoError(t, err, "GetByAggregateID")
+		testutil.AssertEqual(t, len(events), 1, "Event count")
+	})
+}
+
+func TestPostgresStore_ReadFrom(t *testing.T) {
+	ctx, store, _ := setupEventStore(t)
+
+	// Identical timestamps must not affect ordering: position is assigned on append
+	now := time.Now()
+	var appended []*Event
+	for i := 0; i < 5; i++ {
+		event := newStatusUpdateEvent(t, "team-read-from", "Update", "Alice", "alice", now)
+		testutil.AssertNoError(t, store.Append(ctx, event), "Append")
+		appended = append(appended, event)
+	}
+
+	t.Run("assigns gap-free positions", func(t *testing.T) {
+		for i, event := range appended {
+			testutil.AssertEqual(t, event.Position, int64(i+1), "Position")
+		}
+	})
+
+	t.Run("pages by cursor", func(t *testing.T) {
+		var read []*Event
+		var position int64
+		for {
+			batch, err := store.ReadFrom(ctx, position, 2)
+			testutil.AssertNoError(t, err, "ReadFrom")
+			if len(batch) == 0 {
+				break
+			}
+			read = append(read, batch...)
+			position = batch[len(batch)-1].Position
+		}
+
+		testutil.AssertEqual(t, len(read), len(appended), "Event count")
+		for i, event := range read {
+			testutil.AssertEqual(t, event.ID, appended[i].ID, "Event ID")
+		}
+	})
+
+	t.Run("returns nothing past the head", func(t *testing.T) {
+		batch, err := store.ReadFrom(ctx, appended[len(appended)-1].Position, 10)
+		testutil.AssertNoError(t, err, "ReadFrom")
+		testutil.AssertEqual(t, len(batch), 0, "Event count")
+	})
+}
+
 func TestPostgresStore_GetByAggregateID_Empty(t *testing.T) {
 	ctx, store, _ := setupEventStore(t)
 
@@ -256,3 +332,77 @@ case <-time.After(500 * time.Millisecond):
 // Good - no additional events
 }
 }
+
+func TestPostgresStore_SubscribeFrom(t *testing.T) {
+	ctx, store, _ := setupEventStore(t)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// History written before subscribing
+	history := []*Event{
+		newTeamRegisteredEvent(t, "team-catch-up", "Engineering", "#engineering", "", time.Now()),
+		newStatusUpdateEvent(t, "team-catch-up", "First", "Alice", "alice", time.Now()),
+		newStatusUpdateEvent(t, "team-catch-up", "Second", "Bob", "bob", time.Now()),
+	}
+	for _, event := range history {
+		testutil.AssertNoError(t, store.Append(ctx, event), "Append history")
+	}
+
+	eventsCh, err := store.SubscribeFrom(ctx, history[0].Position, []string{})
+	testutil.AssertNoError(t, err, "SubscribeFrom")
+
+	// Live event written after subscribing
+	live := newStatusUpdateEvent(t, "team-catch-up", "Third", "Carol", "carol", time.Now())
+	testutil.AssertNoError(t, store.Append(ctx, live), "Append live")
+
+	want := []*Event{history[1], history[2], live}
+	for _, expected := range want {
+		select {
+		case received := <-eventsCh:
+			testutil.AssertEqual(t, received.ID, expected.ID, "Event ID")
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timeout waiting for event %s", expected.ID)
+		}
+	}
+
+	// No duplicates once caught up
+	select {
+	case unexpected := <-eventsCh:
+		t.Errorf("Received unexpected event: %s", unexpected.ID)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+
+func TestPostgresStore_NotifiesOnCommit(t *testing.T) {
+	ctx, store, testDB := setupEventStore(t)
+
+	listener := pq.NewListener(testDB.ConnectionString(), time.Second, time.Second, nil)
+	defer listener.Close()
+	testutil.AssertNoError(t, listener.Listen(notifyChannel), "Listen")
+
+	t.Run("payload is the event position", func(t *testing.T) {
+		event := newTeamRegisteredEvent(t, "team-notify", "Engineering", "#engineering", "", time.Now())
+		testutil.AssertNoError(t, store.Append(ctx, event), "Append")
+
+		select {
+		case notification := <-listener.Notify:
+			testutil.AssertEqual(t, notification.Extra, strconv.FormatInt(event.Position, 10), "Payload")
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timeout waiting for notification")
+		}
+	})
+
+	t.Run("no notification when the append is rejected", func(t *testing.T) {
+		stale := newTeamRegisteredEvent(t, "team-notify", "Engineering", "#engineering", "", time.Now())
+		if err := store.AppendExpected(ctx, 0, stale); !errors.Is(err, ErrConcurrencyConflict) {
+			t.Fatalf("AppendExpected() error = %v, want ErrConcurrencyConflict", err)
+		}
+
+		select {
+		case notification := <-listener.Notify:
+			t.Errorf("Received unexpected notification: %v", notification)
+		case <-time.After(500 * time.Millisecond):
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrUnknownEventType is returned for event types missing from the registry
+	ErrUnknownEventType = errors.New("unknown event type")
+
+	// ErrInvalidPayload is returned when an event's data does not match the
+	// payload type registered for it
+	ErrInvalidPayload = errors.New("invalid event payload")
+)
+
+// Validator is implemented by payload types with rules beyond their JSON shape
+type Validator interface {
+	Validate() error
+}
+
+// Registry maps event type names to their Go payload types
+type Registry struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[string]reflect.Type)}
+}
+
+// DefaultRegistry holds this application's event types
+var DefaultRegistry = func() *Registry {
+	r := NewRegistry()
+	Register[StatusUpdateSubmittedData](r, StatusUpdateSubmitted)
+	Register[StatusUpdateEditedData](r, StatusUpdateEdited)
+	Register[StatusUpdateDeletedData](r, StatusUpdateDeleted)
+	Register[StatusUpdateCommentedData](r, StatusUpdateCommented)
+	Register[TeamRegisteredData](r, TeamRegistered)
+	Register[TeamUpdatedData](r, TeamUpdated)
+	Register[TeamHealthChangedData](r, TeamHealthChanged)
+	return r
+}()
+
+// Register maps eventType to payload type T
+func Register[T any](r *Registry, eventType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[eventType] = reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Types returns the registered event type names, sorted
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]string, 0, len(r.types))
+	for eventType := range r.types {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// IsRegistered reports whether eventType has a registered payload type
+func (r *Registry) IsRegistered(eventType string) bool {
+	_, err := r.lookup(eventType)
+	return err == nil
+}
+
+// lookup returns the payload type of eventType, counting unknown types
+func (r *Registry) lookup(eventType string) (reflect.Type, error) {
+	r.mu.RLock()
+	payloadType, ok := r.types[eventType]
+	r.mu.RUnlock()
+
+	if !ok {
+		eventsUnknownTypeTotal.WithLabelValues(eventType).Inc()
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+	return payloadType, nil
+}
+
+// Validate checks that event's data strictly matches its registered payload
+// type: no unknown fields, no type mismatches, and the payload's own rules
+func (r *Registry) Validate(event *Event) error {
+	payloadType, err := r.lookup(event.Type)
+	if err != nil {
+		return err
+	}
+
+	payload := reflect.New(payloadType).Interface()
+	decoder := json.NewDecoder(bytes.NewReader(event.Data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(payload); err != nil {
+		return fmt.Errorf("%w: %s event %s: %v", ErrInvalidPayload, event.Type, event.ID, err)
+	}
+
+	if validator, ok := payload.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return fmt.Errorf("%w: %s event %s: %v", ErrInvalidPayload, event.Type, event.ID, err)
+		}
+	}
+	return nil
+}
+
+// Encode marshals payload as the data of an eventType event, rejecting
+// payloads of a different type than the one registered in DefaultRegistry
+func Encode[T any](eventType string, payload T) (json.RawMessage, error) {
+	payloadType, err := DefaultRegistry.lookup(eventType)
+	if err != nil {
+		return nil, err
+	}
+	if got := reflect.TypeOf(payload); got != payloadType {
+		return nil, fmt.Errorf("%w: %s expects %s, got %s", ErrInvalidPayload, eventType, payloadType, got)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", eventType, err)
+	}
+	return data, nil
+}
+
+// Decode unmarshals event's data into its payload type T, which must be the
+// type registered for event.Type in DefaultRegistry
+func Decode[T any](event *Event) (T, error) {
+	var payload T
+
+	payloadType, err := DefaultRegistry.lookup(event.Type)
+	if err != nil {
+		return payload, err
+	}
+	if want := reflect.TypeOf(payload); want != payloadType {
+		return payload, fmt.Errorf("%w: %s carries %s, not %s", ErrInvalidPayload, event.Type, payloadType, want)
+	}
+
+	if err := json.Unmarshal(event.Data, &payload); err != nil {
+		return payload, fmt.Errorf("failed to unmarshal %s event %s: %w", event.Type, event.ID, err)
+	}
+	return payload, nil
+}
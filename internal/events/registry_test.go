@@ -0,0 +1,121 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/status-app/tests/testutil"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	t.Run("round trips a registered payload", func(t *testing.T) {
+		want := TeamRegisteredData{TeamID: "t1", Name: "Engineering", SlackChannel: "C123"}
+
+		data, err := Encode(TeamRegistered, want)
+		testutil.AssertNoError(t, err, "Encode")
+
+		got, err := Decode[TeamRegisteredData](&Event{ID: "e1", Type: TeamRegistered, Data: data})
+		testutil.AssertNoError(t, err, "Decode")
+		testutil.AssertEqual(t, got, want, "payload")
+	})
+
+	t.Run("rejects a payload of the wrong type", func(t *testing.T) {
+		_, err := Encode(TeamRegistered, TeamUpdatedData{TeamID: "t1", Name: "Engineering"})
+		if !errors.Is(err, ErrInvalidPayload) {
+			t.Errorf("Encode() error = %v, want ErrInvalidPayload", err)
+		}
+
+		event := &Event{ID: "e1", Type: TeamRegistered, Data: json.RawMessage(`{"team_id":"t1"}`)}
+		if _, err := Decode[StatusUpdateSubmittedData](event); !errors.Is(err, ErrInvalidPayload) {
+			t.Errorf("Decode() error = %v, want ErrInvalidPayload", err)
+		}
+	})
+
+	t.Run("rejects unknown event types", func(t *testing.T) {
+		_, err := Encode("team.archived", TeamUpdatedData{})
+		if !errors.Is(err, ErrUnknownEventType) {
+			t.Errorf("Encode() error = %v, want ErrUnknownEventType", err)
+		}
+
+		if _, err := Decode[TeamUpdatedData](&Event{ID: "e1", Type: "team.archived"}); !errors.Is(err, ErrUnknownEventType) {
+			t.Errorf("Decode() error = %v, want ErrUnknownEventType", err)
+		}
+	})
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	registry := NewRegistry()
+	Register[TeamRegisteredData](registry, TeamRegistered)
+
+	tests := []struct {
+		name    string
+		event   *Event
+		wantErr error
+	}{
+		{
+			name:  "valid payload",
+			event: &Event{Type: TeamRegistered, Data: json.RawMessage(`{"team_id":"t1","name":"Engineering"}`)},
+		},
+		{
+			name:    "unknown field",
+			event:   &Event{Type: TeamRegistered, Data: json.RawMessage(`{"team_id":"t1","name":"Engineering","title":"x"}`)},
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "wrong field type",
+			event:   &Event{Type: TeamRegistered, Data: json.RawMessage(`{"team_id":1,"name":"Engineering"}`)},
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "missing required field",
+			event:   &Event{Type: TeamRegistered, Data: json.RawMessage(`{"team_id":"t1"}`)},
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "unregistered type",
+			event:   &Event{Type: TeamUpdated, Data: json.RawMessage(`{"team_id":"t1","name":"Engineering"}`)},
+			wantErr: ErrUnknownEventType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registry.Validate(tt.event)
+			if tt.wantErr == nil {
+				testutil.AssertNoError(t, err, "Validate")
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatingStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewValidatingStore(NewMemoryStore(), DefaultRegistry)
+	defer store.Close()
+
+	valid := &Event{ID: "e1", Type: TeamRegistered, AggregateID: "t1", Data: json.RawMessage(`{"team_id":"t1","name":"Engineering"}`)}
+	invalid := &Event{ID: "e2", Type: TeamUpdated, AggregateID: "t1", Data: json.RawMessage(`{"team_id":"t1"}`)}
+
+	// A batch with one invalid event is rejected as a whole
+	if err := store.AppendBatch(ctx, valid, invalid); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("AppendBatch() error = %v, want ErrInvalidPayload", err)
+	}
+	if err := store.AppendExpected(ctx, AnyVersion, invalid); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("AppendExpected() error = %v, want ErrInvalidPayload", err)
+	}
+
+	stored, err := store.GetByAggregateID(ctx, "t1")
+	testutil.AssertNoError(t, err, "GetByAggregateID")
+	testutil.AssertEqual(t, len(stored), 0, "stored events")
+
+	testutil.AssertNoError(t, store.Append(ctx, valid), "Append")
+	stored, err = store.GetByAggregateID(ctx, "t1")
+	testutil.AssertNoError(t, err, "GetByAggregateID")
+	testutil.AssertEqual(t, len(stored), 1, "stored events")
+}
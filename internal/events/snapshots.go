@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Snapshot is the serialized state of an aggregate as of Version, letting
+// rehydration start from it and read only the events after it
+type Snapshot struct {
+	AggregateID   string          `json:"aggregate_id"`
+	AggregateType string          `json:"aggregate_type"`
+	Version       int             `json:"version"`
+	SchemaVersion int             `json:"schema_version"` // version of the State format
+	State         json.RawMessage `json:"state"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// SnapshotStore persists the latest snapshot of each aggregate. Snapshots are
+// a cache: the events remain the source of truth, so losing one only costs a
+// longer rehydration.
+type SnapshotStore interface {
+	// Load returns the latest snapshot of an aggregate, or nil if it has none
+	Load(ctx context.Context, aggregateID string) (*Snapshot, error)
+
+	// Save stores snapshot unless a snapshot at a later version already exists
+	Save(ctx context.Context, snapshot *Snapshot) error
+}
+
+// MemorySnapshotStore is an in-memory SnapshotStore for tests and local development
+type MemorySnapshotStore struct {
+	mu        sync.RWMutex
+	snapshots map[string]Snapshot
+}
+
+func NewMemorySnapshotStore() *MemorySnapshotStore {
+	return &MemorySnapshotStore{snapshots: make(map[string]Snapshot)}
+}
+
+func (s *MemorySnapshotStore) Load(ctx context.Context, aggregateID string) (*Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot, ok := s.snapshots[aggregateID]
+	if !ok {
+		return nil, nil
+	}
+	return &snapshot, nil
+}
+
+func (s *MemorySnapshotStore) Save(ctx context.Context, snapshot *Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.snapshots[snapshot.AggregateID]; ok && existing.Version >= snapshot.Version {
+		return nil
+	}
+	s.snapshots[snapshot.AggregateID] = *snapshot
+	return nil
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/yourusername/status-app/tests/testutil"
+)
+
+func TestMemorySnapshotStore(t *testing.T) {
+	testSnapshotStore(t, NewMemorySnapshotStore())
+}
+
+func TestPostgresSnapshotStore(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	testSnapshotStore(t, store.Snapshots())
+}
+
+func testSnapshotStore(t *testing.T, snapshots SnapshotStore) {
+	ctx := context.Background()
+
+	missing, err := snapshots.Load(ctx, "team-1")
+	testutil.AssertNoError(t, err, "Load missing")
+	if missing != nil {
+		t.Fatalf("Load() = %+v, want nil for an aggregate without snapshots", missing)
+	}
+
+	snapshotAt := func(version int, name string) *Snapshot {
+		return &Snapshot{
+			AggregateID:   "team-1",
+			AggregateType: "team",
+			Version:       version,
+			SchemaVersion: 1,
+			State:         json.RawMessage(`{"name":"` + name + `"}`),
+			CreatedAt:     time.Now().UTC().Truncate(time.Millisecond),
+		}
+	}
+
+	testutil.AssertNoError(t, snapshots.Save(ctx, snapshotAt(100, "Engineering")), "Save v100")
+	testutil.AssertNoError(t, snapshots.Save(ctx, snapshotAt(200, "Product")), "Save v200")
+
+	// An older snapshot, e.g. from a slow concurrent command, never replaces a newer one
+	testutil.AssertNoError(t, snapshots.Save(ctx, snapshotAt(150, "Stale")), "Save v150")
+
+	latest, err := snapshots.Load(ctx, "team-1")
+	testutil.AssertNoError(t, err, "Load")
+	testutil.AssertEqual(t, latest.Version, 200, "Version")
+	testutil.AssertEqual(t, latest.AggregateType, "team", "AggregateType")
+
+	var state map[string]string
+	testutil.AssertNoError(t, json.Unmarshal(latest.State, &state), "Unmarshal state")
+	testutil.AssertEqual(t, state["name"], "Product", "State name")
+}
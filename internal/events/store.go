@@ -2,22 +2,83 @@ package events
 
 import (
 	"context"
+	"errors"
 )
 
+// AnyVersion disables the expected version check when appending events
+const AnyVersion = -1
+
+// ErrConcurrencyConflict is returned when an append's expected aggregate version
+// does not match the version currently stored for that aggregate
+var ErrConcurrencyConflict = errors.New("concurrency conflict")
+
 // Store defines the interface for event storage
 type Store interface {
-	// Append adds a new event to the store
+	// Append adds a new event to the store at the next version of its aggregate
+	// and sets event.Version to the version it was stored at
 	Append(ctx context.Context, event *Event) error
 
+	// AppendExpected adds a new event only if its aggregate is currently at
+	// expectedVersion, returning ErrConcurrencyConflict otherwise
+	AppendExpected(ctx context.Context, expectedVersion int, event *Event) error
+
+	// AppendBatch adds several events in one transaction: either all are stored,
+	// with contiguous versions and positions, or none are. An event with a
+	// non-zero Version is only stored at exactly that version, otherwise the
+	// batch fails with ErrConcurrencyConflict; zero means the next version.
+	AppendBatch(ctx context.Context, events ...*Event) error
+
 	// GetByAggregateID retrieves all events for a specific aggregate
 	GetByAggregateID(ctx context.Context, aggregateID string) ([]*Event, error)
 
+	// GetByAggregateIDFrom retrieves the events of an aggregate with a version
+	// greater than version, as needed on top of a snapshot taken at version
+	GetByAggregateIDFrom(ctx context.Context, aggregateID string, version int) ([]*Event, error)
+
+	// AggregateVersion returns the version of an aggregate's latest event, or
+	// 0 if it has none, without loading its events
+	AggregateVersion(ctx context.Context, aggregateID string) (int, error)
+
+	// GetByCorrelationID retrieves all events whose metadata carries
+	// correlationID, in position order
+	GetByCorrelationID(ctx context.Context, correlationID string) ([]*Event, error)
+
 	// GetAll retrieves all events optionally filtered by type
 	GetAll(ctx context.Context, eventType string, offset, limit int) ([]*Event, error)
 
+	// ReadFrom retrieves up to batchSize events with a global position greater
+	// than position, in position order
+	ReadFrom(ctx context.Context, position int64, batchSize int) ([]*Event, error)
+
 	// Subscribe creates a subscription for new events
 	Subscribe(ctx context.Context, eventTypes []string) (<-chan *Event, error)
 
+	// SubscribeFrom replays events after position and then continues with live
+	// events, delivering each event exactly once and in position order
+	SubscribeFrom(ctx context.Context, position int64, eventTypes []string) (<-chan *Event, error)
+
 	// Close closes the event store connection
 	Close() error
 }
+
+// storedSchemaVersion returns the schema version an event is stored with;
+// events that do not set one are at the initial version
+func storedSchemaVersion(event *Event) int {
+	if event.SchemaVersion == 0 {
+		return InitialSchemaVersion
+	}
+	return event.SchemaVersion
+}
+
+// batchExpectedVersions converts the versions requested on batch events into
+// the aggregate version each append expects
+func batchExpectedVersions(events []*Event) []int {
+	expected := make([]int, len(events))
+	for i, event := range events {
+		expected[i] = AnyVersion
+		if event.Version != 0 {
+			expected[i] = event.Version - 1
+		}
+	}
+	return expected
+}
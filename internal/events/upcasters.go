@@ -0,0 +1,123 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// InitialSchemaVersion is the schema version of event types that never changed,
+// and of events stored before schema versions were recorded
+const InitialSchemaVersion = 1
+
+// Upcaster transforms an event payload from one schema version to the next
+type Upcaster func(data json.RawMessage) (json.RawMessage, error)
+
+// Upcasters is a registry of payload transformations. Events are stored with
+// the schema version they were written at and upcast to the current version
+// on read, so payload structs can evolve without breaking replay.
+type Upcasters struct {
+	mu    sync.RWMutex
+	steps map[string]map[int]Upcaster // event type -> from version -> upcaster
+}
+
+func NewUpcasters() *Upcasters {
+	return &Upcasters{steps: make(map[string]map[int]Upcaster)}
+}
+
+// DefaultUpcasters holds the upcasters for this application's event types.
+// Changing a payload struct incompatibly means registering an upcaster here
+// from the previous version, which makes the new version current.
+var DefaultUpcasters = func() *Upcasters {
+	u := NewUpcasters()
+	u.Register(StatusUpdateSubmitted, 1, upcastStatusUpdateSubmittedV1)
+	return u
+}()
+
+// upcastStatusUpdateSubmittedV1 marks updates from before structured updates
+// as free text
+func upcastStatusUpdateSubmittedV1(data json.RawMessage) (json.RawMessage, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	payload["format"] = UpdateFormatText
+	return json.Marshal(payload)
+}
+
+// Register adds the upcaster from fromVersion to fromVersion+1 for eventType
+func (u *Upcasters) Register(eventType string, fromVersion int, upcaster Upcaster) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.steps[eventType] == nil {
+		u.steps[eventType] = make(map[int]Upcaster)
+	}
+	u.steps[eventType][fromVersion] = upcaster
+}
+
+// CurrentVersion returns the schema version new events of eventType are written at
+func (u *Upcasters) CurrentVersion(eventType string) int {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	current := InitialSchemaVersion
+	for from := range u.steps[eventType] {
+		if from+1 > current {
+			current = from + 1
+		}
+	}
+	return current
+}
+
+// Upcast returns event with its payload at the current schema version. Events
+// already current are returned as is; others are copied, never modified.
+func (u *Upcasters) Upcast(event *Event) (*Event, error) {
+	version := event.SchemaVersion
+	if version == 0 {
+		version = InitialSchemaVersion
+	}
+
+	current := u.CurrentVersion(event.Type)
+	if version == current {
+		return event, nil
+	}
+	if version > current {
+		return nil, fmt.Errorf("event %s has schema version %d, newer than current version %d of %s",
+			event.ID, version, current, event.Type)
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	data := event.Data
+	for ; version < current; version++ {
+		upcaster, ok := u.steps[event.Type][version]
+		if !ok {
+			return nil, fmt.Errorf("no upcaster for %s from schema version %d", event.Type, version)
+		}
+
+		var err error
+		if data, err = upcaster(data); err != nil {
+			return nil, fmt.Errorf("failed to upcast event %s from schema version %d: %w", event.ID, version, err)
+		}
+	}
+
+	upcast := *event
+	upcast.Data = data
+	upcast.SchemaVersion = current
+	return &upcast, nil
+}
+
+// UpcastAll upcasts every event, as loaded for aggregate rehydration
+func (u *Upcasters) UpcastAll(events []*Event) ([]*Event, error) {
+	result := make([]*Event, len(events))
+	for i, event := range events {
+		upcast, err := u.Upcast(event)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = upcast
+	}
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/status-app/tests/testutil"
+)
+
+// renameField returns an upcaster moving a JSON field to a new name
+func renameField(from, to string) Upcaster {
+	return func(data json.RawMessage) (json.RawMessage, error) {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+		payload[to] = payload[from]
+		delete(payload, from)
+		return json.Marshal(payload)
+	}
+}
+
+func TestUpcasters(t *testing.T) {
+	upcasters := NewUpcasters()
+	upcasters.Register(TeamRegistered, 1, renameField("title", "label"))
+	upcasters.Register(TeamRegistered, 2, renameField("label", "name"))
+
+	t.Run("current version follows registered steps", func(t *testing.T) {
+		testutil.AssertEqual(t, upcasters.CurrentVersion(TeamRegistered), 3, "TeamRegistered version")
+		testutil.AssertEqual(t, upcasters.CurrentVersion(TeamUpdated), InitialSchemaVersion, "TeamUpdated version")
+	})
+
+	t.Run("upcasts through every step", func(t *testing.T) {
+		// Events stored before schema versions existed have version 0
+		for _, version := range []int{0, 1} {
+			event := &Event{ID: "e1", Type: TeamRegistered, SchemaVersion: version, Data: json.RawMessage(`{"title":"Engineering"}`)}
+
+			upcast, err := upcasters.Upcast(event)
+			testutil.AssertNoError(t, err, "Upcast")
+			testutil.AssertEqual(t, upcast.SchemaVersion, 3, "SchemaVersion")
+
+			var data TeamRegisteredData
+			testutil.AssertNoError(t, json.Unmarshal(upcast.Data, &data), "Unmarshal")
+			testutil.AssertEqual(t, data.Name, "Engineering", "Name")
+
+			// The stored event is left untouched
+			testutil.AssertEqual(t, string(event.Data), `{"title":"Engineering"}`, "Original data")
+		}
+	})
+
+	t.Run("returns current events as is", func(t *testing.T) {
+		event := &Event{ID: "e2", Type: TeamRegistered, SchemaVersion: 3, Data: json.RawMessage(`{"name":"Product"}`)}
+		upcast, err := upcasters.Upcast(event)
+		testutil.AssertNoError(t, err, "Upcast")
+		if upcast != event {
+			t.Error("expected the same event back")
+		}
+	})
+
+	t.Run("rejects versions newer than current", func(t *testing.T) {
+		event := &Event{ID: "e3", Type: TeamRegistered, SchemaVersion: 4}
+		if _, err := upcasters.Upcast(event); err == nil || !strings.Contains(err.Error(), "newer than current") {
+			t.Errorf("Upcast() error = %v, want newer version error", err)
+		}
+	})
+
+	t.Run("reports missing steps", func(t *testing.T) {
+		gaps := NewUpcasters()
+		gaps.Register(TeamUpdated, 2, renameField("a", "b"))
+
+		event := &Event{ID: "e4", Type: TeamUpdated, SchemaVersion: 1, Data: json.RawMessage(`{}`)}
+		if _, err := gaps.Upcast(event); err == nil || !strings.Contains(err.Error(), "no upcaster") {
+			t.Errorf("Upcast() error = %v, want missing upcaster error", err)
+		}
+	})
+}
+
+func TestDefaultUpcasters_StatusUpdateSubmitted(t *testing.T) {
+	testutil.AssertEqual(t, DefaultUpcasters.CurrentVersion(StatusUpdateSubmitted), 2, "StatusUpdateSubmitted version")
+
+	// Updates from before structured updates read as free text
+	old := &Event{ID: "e1", Type: StatusUpdateSubmitted, SchemaVersion: 1, Data: json.RawMessage(
+		`{"update_id":"u1","team_id":"t1","content":"Shipped it","author":"Alice","slack_user":"alice","timestamp":"2024-01-01T00:00:00Z"}`)}
+	upcast, err := DefaultUpcasters.Upcast(old)
+	testutil.AssertNoError(t, err, "Upcast")
+
+	data, err := Decode[StatusUpdateSubmittedData](upcast)
+	testutil.AssertNoError(t, err, "Decode")
+	testutil.AssertEqual(t, data.Format, UpdateFormatText, "Format")
+	testutil.AssertEqual(t, data.Content, "Shipped it", "Content")
+	if data.Sections != nil {
+		t.Errorf("Sections = %+v, want nil", data.Sections)
+	}
+	testutil.AssertNoError(t, DefaultRegistry.Validate(upcast), "Validate")
+}
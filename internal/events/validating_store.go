@@ -0,0 +1,40 @@
+package events
+
+import "context"
+
+// ValidatingStore wraps a Store and rejects appends whose payloads do not
+// match their registered schema, so malformed events never enter the log
+type ValidatingStore struct {
+	Store
+	registry *Registry
+}
+
+func NewValidatingStore(store Store, registry *Registry) *ValidatingStore {
+	return &ValidatingStore{
+		Store:    store,
+		registry: registry,
+	}
+}
+
+func (s *ValidatingStore) Append(ctx context.Context, event *Event) error {
+	if err := s.registry.Validate(event); err != nil {
+		return err
+	}
+	return s.Store.Append(ctx, event)
+}
+
+func (s *ValidatingStore) AppendExpected(ctx context.Context, expectedVersion int, event *Event) error {
+	if err := s.registry.Validate(event); err != nil {
+		return err
+	}
+	return s.Store.AppendExpected(ctx, expectedVersion, event)
+}
+
+func (s *ValidatingStore) AppendBatch(ctx context.Context, events ...*Event) error {
+	for _, event := range events {
+		if err := s.registry.Validate(event); err != nil {
+			return err
+		}
+	}
+	return s.Store.AppendBatch(ctx, events...)
+}
@@ -0,0 +1,158 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// HeaderKey is the request header carrying the client's idempotency key
+	HeaderKey = "Idempotency-Key"
+
+	// HeaderReplayed marks responses replayed from a stored result
+	HeaderReplayed = "Idempotent-Replayed"
+
+	// HeaderInProgress marks the 409 sent while the first request with a key
+	// is still being handled, telling it apart from the handler's own conflicts
+	HeaderInProgress = "Idempotent-In-Progress"
+
+	// maxKeyLength matches the key column
+	maxKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored and replayed with a result
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+// Middleware makes requests carrying an Idempotency-Key header safe to retry:
+// the first request with a key is handled and its successful response stored,
+// and repeats get that response back without being handled again. Failed
+// responses are not stored, so the request can be retried with the same key.
+func Middleware(store Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderKey)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLength {
+				writeError(w, "Idempotency-Key must be 255 characters or less", http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeError(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			requestFingerprint := fingerprint(r, body)
+
+			// The token makes sure only this request completes or releases
+			// its reservation, even after it times out and is claimed again
+			token := uuid.New().String()
+			existing, err := store.Reserve(r.Context(), key, token, requestFingerprint, time.Now())
+			if err != nil {
+				log.Printf("failed to reserve idempotency key %q: %v", key, err)
+				writeError(w, "failed to check Idempotency-Key", http.StatusInternalServerError)
+				return
+			}
+			if existing != nil {
+				replay(w, existing, requestFingerprint)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			// The response has been sent; record its outcome even if the client
+			// has gone away
+			ctx := context.WithoutCancel(r.Context())
+			if recorder.statusCode >= 200 && recorder.statusCode < 300 {
+				if err := store.Complete(ctx, key, token, recorder.statusCode, storedHeader(recorder.Header()), recorder.body.Bytes()); err != nil {
+					log.Printf("failed to store response for idempotency key %q: %v", key, err)
+				}
+				return
+			}
+			if err := store.Release(ctx, key, token); err != nil {
+				log.Printf("failed to release idempotency key %q: %v", key, err)
+			}
+		})
+	}
+}
+
+// replay answers a request whose key is already in use
+func replay(w http.ResponseWriter, record *Record, requestFingerprint string) {
+	switch {
+	case record.Fingerprint != requestFingerprint:
+		writeError(w, "Idempotency-Key was already used for a different request", http.StatusUnprocessableEntity)
+	case !record.Completed():
+		w.Header().Set(HeaderInProgress, "true")
+		writeError(w, "a request with this Idempotency-Key is still being processed", http.StatusConflict)
+	default:
+		for name, values := range record.Header {
+			for _, value := range values {
+				w.Header().Add(name, value)
+			}
+		}
+		w.Header().Set(HeaderReplayed, "true")
+		w.WriteHeader(record.StatusCode)
+		w.Write(record.Body)
+	}
+}
+
+// fingerprint identifies a request by method, path and body, so a key reused
+// for a different request is detected
+func fingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func storedHeader(header http.Header) http.Header {
+	stored := http.Header{}
+	for _, name := range replayedHeaders {
+		if values := header.Values(name); len(values) > 0 {
+			stored[name] = values
+		}
+	}
+	return stored
+}
+
+func writeError(w http.ResponseWriter, message string, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// responseRecorder passes a response through while keeping a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
@@ -0,0 +1,118 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// countingHandler creates resources, counting how often it actually ran
+type countingHandler struct {
+	calls  int
+	status int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.calls++
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/teams/team-1")
+	w.WriteHeader(h.status)
+	w.Write([]byte(`{"status":"success"}`))
+}
+
+func post(handler http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/teams", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware_ReplaysStoredResponse(t *testing.T) {
+	next := &countingHandler{status: http.StatusCreated}
+	handler := Middleware(NewMemoryStore())(next)
+
+	first := post(handler, "key-1", `{"name":"Engineering"}`)
+	repeat := post(handler, "key-1", `{"name":"Engineering"}`)
+
+	if next.calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", next.calls)
+	}
+	if repeat.Code != first.Code || repeat.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %q, want %d %q", repeat.Code, repeat.Body.String(), first.Code, first.Body.String())
+	}
+	if got := repeat.Header().Get("Location"); got != "/teams/team-1" {
+		t.Errorf("replayed Location = %q, want /teams/team-1", got)
+	}
+	if repeat.Header().Get(HeaderReplayed) != "true" {
+		t.Errorf("expected %s header on the replay", HeaderReplayed)
+	}
+	if first.Header().Get(HeaderReplayed) != "" {
+		t.Errorf("unexpected %s header on the first response", HeaderReplayed)
+	}
+}
+
+func TestMiddleware_MarksInProgressConflicts(t *testing.T) {
+	var repeat *httptest.ResponseRecorder
+	var handler http.Handler
+	handler = Middleware(NewMemoryStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Repeat the request while the first one is still being handled
+		repeat = post(handler, "key-1", `{"name":"Engineering"}`)
+		w.WriteHeader(http.StatusConflict)
+	}))
+
+	first := post(handler, "key-1", `{"name":"Engineering"}`)
+
+	if repeat.Code != http.StatusConflict || repeat.Header().Get(HeaderInProgress) != "true" {
+		t.Errorf("repeat = %d with %s %q, want 409 marked in progress", repeat.Code, HeaderInProgress, repeat.Header().Get(HeaderInProgress))
+	}
+	if first.Code != http.StatusConflict || first.Header().Get(HeaderInProgress) != "" {
+		t.Errorf("handler conflict = %d with %s %q, want an unmarked 409", first.Code, HeaderInProgress, first.Header().Get(HeaderInProgress))
+	}
+}
+
+func TestMiddleware_RejectsKeyReuseForDifferentRequest(t *testing.T) {
+	next := &countingHandler{status: http.StatusCreated}
+	handler := Middleware(NewMemoryStore())(next)
+
+	post(handler, "key-1", `{"name":"Engineering"}`)
+	rec := post(handler, "key-1", `{"name":"Product"}`)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+	if next.calls != 1 {
+		t.Errorf("handler ran %d times, want 1", next.calls)
+	}
+}
+
+func TestMiddleware_RetriesFailedRequests(t *testing.T) {
+	next := &countingHandler{status: http.StatusInternalServerError}
+	handler := Middleware(NewMemoryStore())(next)
+
+	post(handler, "key-1", `{}`)
+	next.status = http.StatusCreated
+	rec := post(handler, "key-1", `{}`)
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if next.calls != 2 {
+		t.Errorf("handler ran %d times, want 2", next.calls)
+	}
+}
+
+func TestMiddleware_PassesThroughWithoutKey(t *testing.T) {
+	next := &countingHandler{status: http.StatusCreated}
+	handler := Middleware(NewMemoryStore())(next)
+
+	post(handler, "", `{}`)
+	post(handler, "", `{}`)
+
+	if next.calls != 2 {
+		t.Errorf("handler ran %d times, want 2", next.calls)
+	}
+}
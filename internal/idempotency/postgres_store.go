@@ -0,0 +1,140 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// PostgresStore keeps idempotency keys in the idempotency_keys table
+type PostgresStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Reserve(ctx context.Context, key, token, fingerprint string, now time.Time) (*Record, error) {
+	// Claim the key unless it holds a live record: an unexpired response, or a
+	// reservation recent enough that its request may still be running
+	query := `
+		INSERT INTO idempotency_keys (key, token, fingerprint, created_at, expires_at)
+		VALUES ($1, $6, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE SET
+			token = EXCLUDED.token,
+			fingerprint = EXCLUDED.fingerprint,
+			status_code = NULL,
+			headers = NULL,
+			body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < $3
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < $5)
+		RETURNING key
+	`
+	var claimed string
+	err := s.db.QueryRowContext(ctx, query, key, fingerprint, now, now.Add(Retention), now.Add(-reservationTimeout), token).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	record, err := s.get(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released between the insert and the read; report it as in progress
+		// so the client retries
+		return &Record{Key: key, Fingerprint: fingerprint, CreatedAt: now}, nil
+	}
+	return record, err
+}
+
+func (s *PostgresStore) get(ctx context.Context, key string) (*Record, error) {
+	query := `
+		SELECT key, fingerprint, status_code, headers, body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE key = $1
+	`
+	var record Record
+	var statusCode sql.NullInt64
+	var headers []byte
+	err := s.db.QueryRowContext(ctx, query, key).Scan(
+		&record.Key,
+		&record.Fingerprint,
+		&statusCode,
+		&headers,
+		&record.Body,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+
+	record.StatusCode = int(statusCode.Int64)
+	if len(headers) > 0 {
+		if err := json.Unmarshal(headers, &record.Header); err != nil {
+			return nil, fmt.Errorf("failed to parse stored headers of idempotency key: %w", err)
+		}
+	}
+	return &record, nil
+}
+
+func (s *PostgresStore) Complete(ctx context.Context, key, token string, statusCode int, header http.Header, body []byte) error {
+	headers, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response headers: %w", err)
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys
+		SET status_code = $3, headers = $4, body = $5
+		WHERE key = $1 AND token = $2 AND status_code IS NULL
+	`, key, token, statusCode, string(headers), body)
+	if err != nil {
+		return fmt.Errorf("failed to store response for idempotency key: %w", err)
+	}
+	return reservationHeld(result)
+}
+
+func (s *PostgresStore) Release(ctx context.Context, key, token string) error {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE key = $1 AND token = $2 AND status_code IS NULL
+	`, key, token)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return reservationHeld(result)
+}
+
+// reservationHeld reports ErrReservationLost if a conditional write on a
+// reservation found no row, because another request claimed the key
+func reservationHeld(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrReservationLost
+	}
+	return nil
+}
+
+// Prune deletes expired keys, returning how many were removed
+func (s *PostgresStore) Prune(ctx context.Context, now time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at < $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
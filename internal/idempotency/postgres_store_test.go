@@ -0,0 +1,77 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yourusername/status-app/tests/testutil"
+)
+
+func TestPostgresStore(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	store := NewPostgresStore(testDB.DB)
+	now := time.Now()
+
+	existing, err := store.Reserve(ctx, "key-1", "token-1", "fingerprint-1", now)
+	testutil.AssertNoError(t, err, "Reserve")
+	if existing != nil {
+		t.Fatalf("Reserve() = %+v, want nil for a new key", existing)
+	}
+
+	// A second request while the first is running sees the reservation
+	existing, err = store.Reserve(ctx, "key-1", "token-2", "fingerprint-1", now)
+	testutil.AssertNoError(t, err, "Reserve in progress")
+	if existing == nil || existing.Completed() {
+		t.Fatalf("Reserve() = %+v, want an incomplete record", existing)
+	}
+
+	header := http.Header{"Location": []string{"/teams/team-1"}}
+	testutil.AssertNoError(t, store.Complete(ctx, "key-1", "token-1", http.StatusCreated, header, []byte(`{"team_id":"team-1"}`)), "Complete")
+
+	existing, err = store.Reserve(ctx, "key-1", "token-3", "fingerprint-1", now)
+	testutil.AssertNoError(t, err, "Reserve completed")
+	testutil.AssertEqual(t, existing.StatusCode, http.StatusCreated, "StatusCode")
+	testutil.AssertEqual(t, existing.Header.Get("Location"), "/teams/team-1", "Location")
+	testutil.AssertEqual(t, string(existing.Body), `{"team_id":"team-1"}`, "Body")
+
+	// Expired keys can be claimed again, and are pruned
+	existing, err = store.Reserve(ctx, "key-1", "token-4", "fingerprint-2", now.Add(Retention+time.Minute))
+	testutil.AssertNoError(t, err, "Reserve expired")
+	if existing != nil {
+		t.Fatalf("Reserve() = %+v, want nil for an expired key", existing)
+	}
+
+	testutil.AssertNoError(t, store.Release(ctx, "key-1", "token-4"), "Release")
+	existing, err = store.Reserve(ctx, "key-1", "token-5", "fingerprint-3", now)
+	testutil.AssertNoError(t, err, "Reserve released")
+	if existing != nil {
+		t.Fatalf("Reserve() = %+v, want nil for a released key", existing)
+	}
+
+	// A reservation that timed out and was claimed again no longer belongs to
+	// the request that made it
+	_, err = store.Reserve(ctx, "key-2", "token-slow", "fingerprint-1", now)
+	testutil.AssertNoError(t, err, "Reserve slow")
+	existing, err = store.Reserve(ctx, "key-2", "token-retry", "fingerprint-1", now.Add(reservationTimeout+time.Second))
+	testutil.AssertNoError(t, err, "Reserve timed out")
+	if existing != nil {
+		t.Fatalf("Reserve() = %+v, want nil for a timed out reservation", existing)
+	}
+	if err := store.Complete(ctx, "key-2", "token-slow", http.StatusCreated, header, []byte(`{}`)); !errors.Is(err, ErrReservationLost) {
+		t.Errorf("Complete() with a lost reservation error = %v, want ErrReservationLost", err)
+	}
+	if err := store.Release(ctx, "key-2", "token-slow"); !errors.Is(err, ErrReservationLost) {
+		t.Errorf("Release() with a lost reservation error = %v, want ErrReservationLost", err)
+	}
+	testutil.AssertNoError(t, store.Complete(ctx, "key-2", "token-retry", http.StatusCreated, header, []byte(`{}`)), "Complete retry")
+
+	pruned, err := store.Prune(ctx, now.Add(2*Retention))
+	testutil.AssertNoError(t, err, "Prune")
+	testutil.AssertEqual(t, pruned, int64(2), "Pruned keys")
+}
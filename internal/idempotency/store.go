@@ -0,0 +1,122 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Record is what is remembered about a request made with an idempotency key
+type Record struct {
+	Key         string
+	Token       string // identifies the reservation that holds the key
+	Fingerprint string // hash of the method, path and body the key was first used with
+
+	// StatusCode is zero while the first request is still being handled
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Completed reports whether the response to the key's first request is stored
+func (r *Record) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// ErrReservationLost is returned when completing or releasing a reservation
+// that timed out and was claimed by another request, which now owns the key
+var ErrReservationLost = errors.New("idempotency key reservation was claimed by another request")
+
+// Store remembers idempotency keys and the responses they produced
+type Store interface {
+	// Reserve claims key for a request with fingerprint, under token. It
+	// returns nil if the key was claimed, or the existing record if the key is
+	// already in use. Expired keys, and reservations older than
+	// reservationTimeout that never completed, can be claimed again.
+	Reserve(ctx context.Context, key, token, fingerprint string, now time.Time) (*Record, error)
+
+	// Complete stores the response for a key reserved under token, failing
+	// with ErrReservationLost if another reservation holds the key
+	Complete(ctx context.Context, key, token string, statusCode int, header http.Header, body []byte) error
+
+	// Release forgets a key reserved under token, so the request can be
+	// retried with it. It fails with ErrReservationLost if another
+	// reservation holds the key.
+	Release(ctx context.Context, key, token string) error
+}
+
+const (
+	// Retention is how long a key's response is remembered
+	Retention = 24 * time.Hour
+
+	// reservationTimeout is how long a reservation blocks retries of its key;
+	// after it, the request that made it is presumed lost
+	reservationTimeout = time.Minute
+)
+
+// MemoryStore is an in-memory Store for tests and local development
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+func (s *MemoryStore) Reserve(ctx context.Context, key, token, fingerprint string, now time.Time) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[key]; ok && !claimable(existing, now) {
+		return &existing, nil
+	}
+
+	s.records[key] = Record{
+		Key:         key,
+		Token:       token,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(Retention),
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(ctx context.Context, key, token string, statusCode int, header http.Header, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if !ok || record.Token != token || record.Completed() {
+		return ErrReservationLost
+	}
+	record.StatusCode = statusCode
+	record.Header = header.Clone()
+	record.Body = append([]byte(nil), body...)
+	s.records[key] = record
+	return nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[key]; !ok || record.Token != token || record.Completed() {
+		return ErrReservationLost
+	}
+	delete(s.records, key)
+	return nil
+}
+
+// claimable reports whether an existing record may be replaced by a new reservation
+func claimable(record Record, now time.Time) bool {
+	if now.After(record.ExpiresAt) {
+		return true
+	}
+	return !record.Completed() && now.Sub(record.CreatedAt) > reservationTimeout
+}
@@ -0,0 +1,40 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_RejectsLostReservations(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	if existing, err := store.Reserve(ctx, "key-1", "token-slow", "fingerprint-1", now); err != nil || existing != nil {
+		t.Fatalf("Reserve() = %+v, %v, want the key claimed", existing, err)
+	}
+
+	// The slow request's reservation times out and a retry claims the key
+	later := now.Add(reservationTimeout + time.Second)
+	if existing, err := store.Reserve(ctx, "key-1", "token-retry", "fingerprint-1", later); err != nil || existing != nil {
+		t.Fatalf("Reserve() = %+v, %v, want the timed out key claimed again", existing, err)
+	}
+
+	if err := store.Complete(ctx, "key-1", "token-slow", http.StatusCreated, http.Header{}, []byte(`{"slow":true}`)); !errors.Is(err, ErrReservationLost) {
+		t.Errorf("Complete() with a lost reservation error = %v, want ErrReservationLost", err)
+	}
+	if err := store.Release(ctx, "key-1", "token-slow"); !errors.Is(err, ErrReservationLost) {
+		t.Errorf("Release() with a lost reservation error = %v, want ErrReservationLost", err)
+	}
+
+	if err := store.Complete(ctx, "key-1", "token-retry", http.StatusCreated, http.Header{}, []byte(`{"retry":true}`)); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	existing, err := store.Reserve(ctx, "key-1", "token-3", "fingerprint-1", later)
+	if err != nil || existing == nil || string(existing.Body) != `{"retry":true}` {
+		t.Errorf("Reserve() = %+v, %v, want the retry's response", existing, err)
+	}
+}
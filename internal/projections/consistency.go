@@ -0,0 +1,94 @@
+package projections
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// positionPollInterval is how often WaitForPosition re-reads the checkpoint
+const positionPollInterval = 25 * time.Millisecond
+
+// ErrPositionNotReached is returned by WaitForPosition when the read models
+// did not catch up in time
+var ErrPositionNotReached = errors.New("read models have not reached the requested position")
+
+// ErrPositionDeadLettered is returned by WaitForPosition when the checkpoint
+// passed the requested position but an event up to it was dead-lettered, so
+// the read models do not reflect it
+var ErrPositionDeadLettered = errors.New("an event up to the requested position failed to apply")
+
+// Position returns the log position of the last event applied to the live
+// read models (0 if none)
+func (r *Repository) Position(ctx context.Context) (int64, error) {
+	var position int64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT position FROM checkpoints WHERE projection = $1`,
+		defaultCheckpoint,
+	).Scan(&position)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	return position, nil
+}
+
+// WaitForPosition blocks until the live read models have applied the event at
+// position, so a read reflects a command whose result reported it. It fails
+// with ErrPositionNotReached once timeout passes, and with
+// ErrPositionDeadLettered if an event up to position is still dead-lettered.
+func (r *Repository) WaitForPosition(ctx context.Context, position int64, timeout time.Duration) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(positionPollInterval)
+	defer ticker.Stop()
+
+	for {
+		current, err := r.Position(ctx)
+		if err == nil && current >= position {
+			if err := r.checkDeadLetters(ctx, position); err != nil {
+				if errors.Is(err, ErrPositionDeadLettered) {
+					recordPositionWait("dead_lettered", time.Since(start))
+				}
+				return err
+			}
+			recordPositionWait("reached", time.Since(start))
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				recordPositionWait("timeout", time.Since(start))
+				return fmt.Errorf("%w: at %d, waited %s for %d", ErrPositionNotReached, current, timeout, position)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkDeadLetters fails with ErrPositionDeadLettered if the live read models
+// skipped an event at or below position that has not been resolved
+func (r *Repository) checkDeadLetters(ctx context.Context, position int64) error {
+	var eventPosition int64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT position FROM dead_letters
+		WHERE projection = $1 AND position <= $2 AND status IN ($3, $4)
+		ORDER BY position ASC
+		LIMIT 1
+	`, defaultCheckpoint, position, DeadLetterPending, DeadLetterFailed).Scan(&eventPosition)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check dead letters: %w", err)
+	}
+	return fmt.Errorf("%w: event at %d is dead-lettered", ErrPositionDeadLettered, eventPosition)
+}
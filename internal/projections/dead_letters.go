@@ -0,0 +1,253 @@
+package projections
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/status-app/internal/events"
+)
+
+const (
+	// Dead letter statuses
+	DeadLetterPending = "pending" // retried automatically with backoff
+	DeadLetterFailed  = "failed"  // gave up after maxDeadLetterAttempts, needs an admin
+	DeadLetterSkipped = "skipped" // an admin decided not to apply the event
+
+	// maxDeadLetterAttempts is the number of attempts before a dead letter stops
+	// being retried automatically
+	maxDeadLetterAttempts = 8
+
+	// deadLetterBaseBackoff is the delay before the first retry; it doubles per attempt
+	deadLetterBaseBackoff = 30 * time.Second
+
+	// deadLetterMaxBackoff caps the delay between retries
+	deadLetterMaxBackoff = time.Hour
+
+	// deadLetterPollInterval is how often due dead letters are retried
+	deadLetterPollInterval = 30 * time.Second
+)
+
+// ErrDeadLetterNotFound is returned when no dead letter exists for an event
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// deadLetterBackoff returns the delay before retrying after the given number of attempts
+func deadLetterBackoff(attempts int) time.Duration {
+	backoff := deadLetterBaseBackoff
+	for i := 1; i < attempts && backoff < deadLetterMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > deadLetterMaxBackoff {
+		return deadLetterMaxBackoff
+	}
+	return backoff
+}
+
+// deadLetter records an event that failed to apply and advances the checkpoint
+// past it in one transaction, so the projector keeps up with the rest of the log
+func (p *Projector) deadLetter(ctx context.Context, event *events.Event, cause error) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	position, err := p.lockCheckpoint(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// Another projector applied the event in the meantime
+	if event.Position <= position {
+		return nil
+	}
+
+	query := `
+		INSERT INTO dead_letters (projection, event_id, position, event_type, error, attempts, status, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, 1, $6, $7, NOW(), NOW())
+		ON CONFLICT (projection, event_id) DO UPDATE
+		SET error = EXCLUDED.error, attempts = dead_letters.attempts + 1, updated_at = EXCLUDED.updated_at
+	`
+	_, err = tx.ExecContext(ctx, query,
+		p.checkpoint,
+		event.ID,
+		event.Position,
+		event.Type,
+		cause.Error(),
+		DeadLetterPending,
+		time.Now().Add(deadLetterBackoff(1)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record dead letter: %w", err)
+	}
+
+	if err := p.saveCheckpoint(ctx, tx, event.Position); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	projectionDeadLettersTotal.WithLabelValues(p.checkpoint).Inc()
+	log.Printf("dead-lettered event %s (%s) at position %d: %v", event.ID, event.Type, event.Position, cause)
+	return nil
+}
+
+// retryDeadLetters periodically retries pending dead letters that are due
+func (p *Projector) retryDeadLetters(ctx context.Context) {
+	ticker := time.NewTicker(deadLetterPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.retryDueDeadLetters(ctx); err != nil {
+				log.Printf("failed to retry dead letters: %v", err)
+			}
+		}
+	}
+}
+
+func (p *Projector) retryDueDeadLetters(ctx context.Context) error {
+	query := `
+		SELECT event_id
+		FROM dead_letters
+		WHERE projection = $1 AND status = $2 AND next_attempt_at <= $3
+		ORDER BY position ASC
+		LIMIT 100
+	`
+	rows, err := p.db.QueryContext(ctx, query, p.checkpoint, DeadLetterPending, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var eventIDs []string
+	for rows.Next() {
+		var eventID string
+		if err := rows.Scan(&eventID); err != nil {
+			rows.Close()
+			return err
+		}
+		eventIDs = append(eventIDs, eventID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, eventID := range eventIDs {
+		if err := p.RetryDeadLetter(ctx, eventID); err != nil {
+			log.Printf("retry of dead-lettered event %s failed: %v", eventID, err)
+		}
+	}
+	return nil
+}
+
+// RetryDeadLetter re-applies a dead-lettered event. On success the dead letter is
+// removed; on failure its attempt count grows and its next retry is backed off.
+func (p *Projector) RetryDeadLetter(ctx context.Context, eventID string) error {
+	var position int64
+	var attempts int
+	err := p.db.QueryRowContext(ctx,
+		`SELECT position, attempts FROM dead_letters WHERE projection = $1 AND event_id = $2`,
+		p.checkpoint, eventID,
+	).Scan(&position, &attempts)
+	if err == sql.ErrNoRows {
+		return ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load dead letter: %w", err)
+	}
+
+	batch, err := p.eventStore.ReadFrom(ctx, position-1, 1)
+	if err != nil {
+		return fmt.Errorf("failed to load event: %w", err)
+	}
+	if len(batch) == 0 || batch[0].ID != eventID {
+		return fmt.Errorf("event %s not found at position %d", eventID, position)
+	}
+	event := batch[0]
+
+	applyErr := p.applyDeadLetter(ctx, event)
+	if applyErr == nil {
+		log.Printf("re-applied dead-lettered event %s", eventID)
+		return nil
+	}
+
+	attempts++
+	status := DeadLetterPending
+	if attempts >= maxDeadLetterAttempts {
+		status = DeadLetterFailed
+	}
+
+	query := `
+		UPDATE dead_letters
+		SET attempts = $3, error = $4, status = $5, next_attempt_at = $6, updated_at = NOW()
+		WHERE projection = $1 AND event_id = $2
+	`
+	_, err = p.db.ExecContext(ctx, query,
+		p.checkpoint,
+		eventID,
+		attempts,
+		applyErr.Error(),
+		status,
+		time.Now().Add(deadLetterBackoff(attempts)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update dead letter: %w", err)
+	}
+
+	return applyErr
+}
+
+// applyDeadLetter applies event and removes its dead letter in one transaction.
+// The checkpoint already moved past the event, so it is not consulted.
+func (p *Projector) applyDeadLetter(ctx context.Context, event *events.Event) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, handle := p.handlerFor(event.Type); handle != nil {
+		if err := handle(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM dead_letters WHERE projection = $1 AND event_id = $2`,
+		p.checkpoint, event.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove dead letter: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// SkipDeadLetter marks a dead letter as skipped so it is no longer retried
+func (p *Projector) SkipDeadLetter(ctx context.Context, eventID string) error {
+	result, err := p.db.ExecContext(ctx,
+		`UPDATE dead_letters SET status = $3, updated_at = NOW() WHERE projection = $1 AND event_id = $2`,
+		p.checkpoint, eventID, DeadLetterSkipped,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to skip dead letter: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrDeadLetterNotFound
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package projections
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourusername/status-app/tests/testutil"
+)
+
+func TestDeadLetterBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{50, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := deadLetterBackoff(tt.attempts); got != tt.want {
+			t.Errorf("deadLetterBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestProjector_DeadLetters(t *testing.T) {
+	t.Run("records failed event and moves past it", func(t *testing.T) {
+		env := setupProjector(t)
+		now := time.Now()
+
+		// Status update for a team that has no row yet violates the foreign key
+		orphan := newStatusUpdateEvent(t, "team-late", "Early update", "Alice", "alice", now)
+		env.appendEvent(orphan)
+		env.appendEvent(newTeamRegisteredEvent(t, "team-other", "Other", "#other", "", now))
+
+		env.rebuild()
+
+		deadLetters, err := env.repo.GetDeadLetters(env.ctx, DeadLetterPending)
+		testutil.AssertNoError(t, err, "GetDeadLetters")
+		if len(deadLetters) != 1 {
+			t.Fatalf("GetDeadLetters() returned %d dead letters, want 1", len(deadLetters))
+		}
+		testutil.AssertEqual(t, deadLetters[0].EventID, orphan.ID, "Dead letter event ID")
+		testutil.AssertEqual(t, deadLetters[0].Attempts, 1, "Dead letter attempts")
+
+		// The event after the failed one was still applied
+		_, err = env.repo.GetTeam(env.ctx, "team-other")
+		testutil.AssertNoError(t, err, "GetTeam")
+	})
+
+	t.Run("does not move past an event it cannot dead-letter", func(t *testing.T) {
+		env := setupProjector(t)
+		now := time.Now()
+
+		orphan := newStatusUpdateEvent(t, "team-late", "Early update", "Alice", "alice", now)
+		env.appendEvent(orphan)
+		env.appendEvent(newTeamRegisteredEvent(t, "team-other", "Other", "#other", "", now))
+
+		// Dead-lettering fails until the table is back
+		_, err := env.testDB.DB.ExecContext(env.ctx, `ALTER TABLE dead_letters RENAME TO dead_letters_away`)
+		testutil.AssertNoError(t, err, "rename dead_letters")
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			env.testDB.DB.ExecContext(env.ctx, `ALTER TABLE dead_letters_away RENAME TO dead_letters`)
+		}()
+
+		env.rebuild()
+
+		deadLetters, err := env.repo.GetDeadLetters(env.ctx, DeadLetterPending)
+		testutil.AssertNoError(t, err, "GetDeadLetters")
+		if len(deadLetters) != 1 {
+			t.Fatalf("GetDeadLetters() returned %d dead letters, want 1", len(deadLetters))
+		}
+		testutil.AssertEqual(t, deadLetters[0].EventID, orphan.ID, "Dead letter event ID")
+
+		_, err = env.repo.GetTeam(env.ctx, "team-other")
+		testutil.AssertNoError(t, err, "GetTeam")
+	})
+
+	t.Run("retry applies the event once it can succeed", func(t *testing.T) {
+		env := setupProjector(t)
+		now := time.Now()
+
+		orphan := newStatusUpdateEvent(t, "team-late", "Early update", "Alice", "alice", now)
+		env.appendEvent(orphan)
+		env.rebuild()
+
+		// Retrying before the team exists fails again and backs off
+		if err := env.projector.RetryDeadLetter(env.ctx, orphan.ID); err == nil {
+			t.Fatal("RetryDeadLetter() expected error before team exists, got nil")
+		}
+		deadLetters, err := env.repo.GetDeadLetters(env.ctx, "")
+		testutil.AssertNoError(t, err, "GetDeadLetters")
+		testutil.AssertEqual(t, deadLetters[0].Attempts, 2, "Dead letter attempts")
+
+		env.appendEvent(newTeamRegisteredEvent(t, "team-late", "Late", "#late", "", now))
+		env.rebuild()
+
+		testutil.AssertNoError(t, env.projector.RetryDeadLetter(env.ctx, orphan.ID), "RetryDeadLetter")
+
+		updates, err := env.repo.GetTeamUpdates(env.ctx, "team-late", 10)
+		testutil.AssertNoError(t, err, "GetTeamUpdates")
+		testutil.AssertEqual(t, len(updates), 1, "Update count")
+
+		deadLetters, err = env.repo.GetDeadLetters(env.ctx, "")
+		testutil.AssertNoError(t, err, "GetDeadLetters")
+		testutil.AssertEqual(t, len(deadLetters), 0, "Dead letter count")
+	})
+
+	t.Run("skip stops retries", func(t *testing.T) {
+		env := setupProjector(t)
+
+		orphan := newStatusUpdateEvent(t, "team-late", "Early update", "Alice", "alice", time.Now())
+		env.appendEvent(orphan)
+		env.rebuild()
+
+		testutil.AssertNoError(t, env.projector.SkipDeadLetter(env.ctx, orphan.ID), "SkipDeadLetter")
+
+		skipped, err := env.repo.GetDeadLetters(env.ctx, DeadLetterSkipped)
+		testutil.AssertNoError(t, err, "GetDeadLetters")
+		testutil.AssertEqual(t, len(skipped), 1, "Skipped dead letter count")
+
+		if err := env.projector.SkipDeadLetter(env.ctx, "unknown"); err != ErrDeadLetterNotFound {
+			t.Errorf("SkipDeadLetter() error = %v, want ErrDeadLetterNotFound", err)
+		}
+	})
+}
@@ -49,6 +49,47 @@ var (
 		},
 		[]string{"projection"},
 	)
+
+	projectionDeadLettersTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "status_app",
+			Subsystem: "projections",
+			Name:      "dead_letters_total",
+			Help:      "Total number of events dead-lettered by checkpoint",
+		},
+		[]string{"checkpoint"},
+	)
+
+	projectionCheckpointPosition = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "status_app",
+			Subsystem: "projections",
+			Name:      "checkpoint_position",
+			Help:      "Global position of the last event applied by each checkpoint",
+		},
+		[]string{"checkpoint"},
+	)
+
+	projectionPositionWaitDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "status_app",
+			Subsystem: "projections",
+			Name:      "position_wait_duration_seconds",
+			Help:      "Time reads spent waiting for the read models to reach a min_position, by result",
+			Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+		},
+		[]string{"result"},
+	)
+
+	projectionReplayEventsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "status_app",
+			Subsystem: "projections",
+			Name:      "replay_events_total",
+			Help:      "Total number of events read while replaying the log by checkpoint",
+		},
+		[]string{"checkpoint"},
+	)
 )
 
 // recordProjectionUpdate records metrics for a successful projection update
@@ -67,3 +108,15 @@ func recordProjectionUpdate(projection string, eventTime time.Time, duration tim
 func recordProjectionError(projection string) {
 	projectionErrorsTotal.WithLabelValues(projection).Inc()
 }
+
+// recordReplayProgress records a replayed batch and the position it reached
+func recordReplayProgress(checkpoint string, events int, position int64) {
+	projectionReplayEventsTotal.WithLabelValues(checkpoint).Add(float64(events))
+	projectionCheckpointPosition.WithLabelValues(checkpoint).Set(float64(position))
+}
+
+// recordPositionWait records how long a read waited for the read models to
+// reach its min_position, and whether they did
+func recordPositionWait(result string, waited time.Duration) {
+	projectionPositionWaitDuration.WithLabelValues(result).Observe(waited.Seconds())
+}
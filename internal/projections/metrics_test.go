@@ -93,6 +93,26 @@ func TestProjectionMetrics_ErrorsTotal(t *testing.T) {
 	}
 }
 
+func TestProjectionMetrics_ReplayProgress(t *testing.T) {
+	// Reset metrics
+	projectionReplayEventsTotal.Reset()
+	projectionCheckpointPosition.Reset()
+	
+	recordReplayProgress("read_models", 500, 500)
+	recordReplayProgress("read_models", 120, 620)
+	
+	replayed := getCounterValue(t, projectionReplayEventsTotal, "read_models")
+	position := getGaugeValue(t, projectionCheckpointPosition, "read_models")
+	
+	if replayed != 620 {
+		t.Errorf("Expected 620 replayed events, got %f", replayed)
+	}
+	
+	if position != 620 {
+		t.Errorf("Expected checkpoint position 620, got %f", position)
+	}
+}
+
 // Helper functions
 
 func getCounterValue(t *testing.T, counter *prometheus.CounterVec, label string) float64 {
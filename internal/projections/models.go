@@ -9,16 +9,85 @@ type Team struct {
 	SlackChannel string    `json:"slack_channel"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Health is "green", "amber" or "red", or empty if the team was never rated
+	Health          string     `json:"health,omitempty"`
+	HealthReason    string     `json:"health_reason,omitempty"`
+	HealthChangedAt *time.Time `json:"health_changed_at,omitempty"`
+}
+
+// TeamHealth is a team's current health and how it got there
+type TeamHealth struct {
+	TeamID    string          `json:"team_id"`
+	Health    string          `json:"health,omitempty"`
+	Reason    string          `json:"reason,omitempty"`
+	ChangedAt *time.Time      `json:"changed_at,omitempty"`
+	History   []*HealthChange `json:"history"`
+}
+
+// HealthChange is one change of a team's health
+type HealthChange struct {
+	TeamID         string    `json:"team_id"`
+	Health         string    `json:"health"`
+	PreviousHealth string    `json:"previous_health,omitempty"`
+	Reason         string    `json:"reason,omitempty"`
+	ChangedBy      string    `json:"changed_by,omitempty"`
+	ChangedAt      time.Time `json:"changed_at"`
 }
 
 // StatusUpdate represents a status update in the read model
 type StatusUpdate struct {
-	UpdateID  string    `json:"update_id"`
-	TeamID    string    `json:"team_id"`
-	Content   string    `json:"content"`
-	Author    string    `json:"author"`
-	SlackUser string    `json:"slack_user"`
-	CreatedAt time.Time `json:"created_at"`
+	UpdateID  string     `json:"update_id"`
+	TeamID    string     `json:"team_id"`
+	Content   string     `json:"content"`
+	Author    string     `json:"author"`
+	SlackUser string     `json:"slack_user"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+
+	SlackMessage *SlackMessageLink `json:"slack_message,omitempty"`
+
+	// Format is "text" or "structured"; structured updates have Sections
+	Format   string          `json:"format"`
+	Sections *UpdateSections `json:"sections,omitempty"`
+}
+
+// UpdateSections are the sections of a structured status update
+type UpdateSections struct {
+	Done     string `json:"done,omitempty"`
+	Next     string `json:"next,omitempty"`
+	Blockers string `json:"blockers,omitempty"`
+	Health   string `json:"health,omitempty"`
+}
+
+// SlackMessageLink identifies the Slack message a status update was posted as
+type SlackMessageLink struct {
+	Channel   string `json:"channel"`
+	TS        string `json:"ts"`
+	ThreadTS  string `json:"thread_ts,omitempty"`
+	Permalink string `json:"permalink,omitempty"`
+}
+
+// UpdateEdit is one edit of a status update, with the content it replaced
+type UpdateEdit struct {
+	UpdateID        string    `json:"update_id"`
+	PreviousContent string    `json:"previous_content"`
+	Content         string    `json:"content"`
+	EditedBy        string    `json:"edited_by,omitempty"`
+	EditedAt        time.Time `json:"edited_at"`
+}
+
+// UpdateComment is a comment on a status update, such as a reply in the Slack
+// thread under it
+type UpdateComment struct {
+	CommentID    string            `json:"comment_id"`
+	UpdateID     string            `json:"update_id"`
+	TeamID       string            `json:"team_id"`
+	Content      string            `json:"content"`
+	Author       string            `json:"author"`
+	SlackUser    string            `json:"slack_user,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	SlackMessage *SlackMessageLink `json:"slack_message,omitempty"`
 }
 
 // TeamSummary provides aggregate information about a team
@@ -28,3 +97,17 @@ type TeamSummary struct {
 	LastUpdateAt       time.Time `json:"last_update_at"`
 	UniqueContributors int       `json:"unique_contributors"`
 }
+
+// DeadLetter is an event the projector failed to apply
+type DeadLetter struct {
+	Projection    string    `json:"projection"`
+	EventID       string    `json:"event_id"`
+	Position      int64     `json:"position"`
+	EventType     string    `json:"event_type"`
+	Error         string    `json:"error"`
+	Attempts      int       `json:"attempts"`
+	Status        string    `json:"status"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
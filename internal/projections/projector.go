@@ -3,7 +3,7 @@ package projections
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -12,38 +12,79 @@ import (
 )
 
 const (
-	// maxEventsPerRebuild is the maximum number of events to load during projection rebuild
-	// In production, this should be replaced with pagination for unlimited event handling
-	maxEventsPerRebuild = 10000
+	// replayBatchSize is the number of events read per query during replay,
+	// which bounds replay memory use regardless of log size
+	replayBatchSize = 500
+
+	// defaultCheckpoint names the checkpoint tracking the live read models
+	defaultCheckpoint = "read_models"
+
+	// recordRetryBaseBackoff is the delay before retrying an event that could be
+	// neither applied nor dead-lettered; it doubles per attempt
+	recordRetryBaseBackoff = time.Second
+
+	// recordRetryMaxBackoff caps the delay between those retries
+	recordRetryMaxBackoff = time.Minute
 )
 
+// errEventNotRecorded marks failures that left an event neither applied nor
+// dead-lettered, so the projector must not move past it
+var errEventNotRecorded = errors.New("event was neither applied nor dead-lettered")
+
 // Projector builds read models from events
 type Projector struct {
-	eventStore events.Store
-	db         *sql.DB
+	eventStore  events.Store
+	db          *sql.DB
+	checkpoint  string
+	batchSize   int
+	tableSuffix string // non-empty when projecting into shadow tables
+	upcasters   *events.Upcasters
+	handlers    map[string]projectionHandler
 }
 
 func NewProjector(eventStore events.Store, db *sql.DB) *Projector {
-	return &Projector{
+	p := &Projector{
 		eventStore: eventStore,
 		db:         db,
+		checkpoint: defaultCheckpoint,
+		batchSize:  replayBatchSize,
+		upcasters:  events.DefaultUpcasters,
 	}
+
+	p.handlers = map[string]projectionHandler{
+		events.StatusUpdateSubmitted: on("status_updates", p.handleStatusUpdateSubmitted),
+		events.StatusUpdateEdited:    on("status_updates", p.handleStatusUpdateEdited),
+		events.StatusUpdateDeleted:   on("status_updates", p.handleStatusUpdateDeleted),
+		events.StatusUpdateCommented: on("update_comments", p.handleStatusUpdateCommented),
+		events.TeamRegistered:        on("teams", p.handleTeamRegistered),
+		events.TeamUpdated:           on("teams", p.handleTeamUpdated),
+		events.TeamHealthChanged:     on("team_health_history", p.handleTeamHealthChanged),
+	}
+
+	return p
+}
+
+// table returns the name of the read model table this projector writes to
+func (p *Projector) table(name string) string {
+	return name + p.tableSuffix
 }
 
 // Start begins processing events and building projections
 func (p *Projector) Start(ctx context.Context) error {
-	// Initial projection rebuild from all events
-	if err := p.rebuildProjections(ctx); err != nil {
-		return fmt.Errorf("failed to rebuild projections: %w", err)
+	// Bring the read models up to date in batches before going live
+	position, err := p.replay(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to replay events: %w", err)
 	}
 
-	// Subscribe to new events
-	eventsCh, err := p.eventStore.Subscribe(ctx, []string{})
+	// Follow new events through a catch-up subscription from where the replay
+	// stopped, so events appended during the replay are not lost
+	eventsCh, err := p.eventStore.SubscribeFrom(ctx, position, []string{})
 	if err != nil {
 		return fmt.Errorf("failed to subscribe to events: %w", err)
 	}
 
-	// Process new events as they arrive
+	// Process events as they arrive
 	go func() {
 		for {
 			select {
@@ -52,8 +93,11 @@ func (p *Projector) Start(ctx context.Context) error {
 					log.Println("event channel closed, stopping projection subscription")
 					return
 				}
-				if err := p.processEvent(ctx, event); err != nil {
-					log.Printf("failed to process event %s: %v", event.ID, err)
+				if err := p.recordEvent(ctx, event); err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					log.Printf("failed to process event %s [%s]: %v", event.ID, eventMetadata(event), err)
 				}
 			case <-ctx.Done():
 				return
@@ -61,44 +105,138 @@ func (p *Projector) Start(ctx context.Context) error {
 		}
 	}()
 
+	go p.retryDeadLetters(ctx)
+
 	return nil
 }
 
-func (p *Projector) rebuildProjections(ctx context.Context) error {
-	// Get all events
-	allEvents, err := p.eventStore.GetAll(ctx, "", 0, maxEventsPerRebuild)
+// replay applies every event after the checkpoint, reading the log in batches
+// of p.batchSize so memory use stays bounded however long the log grows. It
+// returns the position of the last event read and stops early if ctx is cancelled.
+func (p *Projector) replay(ctx context.Context) (int64, error) {
+	position, err := p.loadCheckpoint(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get events: %w", err)
+		return 0, fmt.Errorf("failed to load checkpoint: %w", err)
 	}
 
-	// Process each event to rebuild projections
-	for _, event := range allEvents {
-		if err := p.processEvent(ctx, event); err != nil {
-			log.Printf("warning: failed to process event %s during rebuild: %v", event.ID, err)
+	for {
+		if err := ctx.Err(); err != nil {
+			return position, err
+		}
+
+		batch, err := p.eventStore.ReadFrom(ctx, position, p.batchSize)
+		if err != nil {
+			return position, fmt.Errorf("failed to read events after position %d: %w", position, err)
+		}
+		if len(batch) == 0 {
+			return position, nil
+		}
+
+		for _, event := range batch {
+			if err := p.recordEvent(ctx, event); err != nil {
+				if ctx.Err() != nil {
+					return position, ctx.Err()
+				}
+				log.Printf("warning: failed to process event %s during replay [%s]: %v", event.ID, eventMetadata(event), err)
+			}
+			position = event.Position
 		}
+
+		recordReplayProgress(p.checkpoint, len(batch), position)
 	}
+}
 
-	return nil
+// eventMetadata returns the event's metadata for log lines, tolerating
+// events with malformed metadata
+func eventMetadata(event *events.Event) events.Metadata {
+	metadata, _ := event.ParseMetadata()
+	return metadata
+}
+
+// eventHandler applies a single event to the read models within tx
+type eventHandler func(ctx context.Context, tx *sql.Tx, event *events.Event) error
+
+// projectionHandler names the projection an event type updates and applies it
+type projectionHandler struct {
+	projection string
+	handle     eventHandler
+}
+
+// on adapts a handler taking a decoded payload of type T into a projectionHandler
+func on[T any](projection string, apply func(ctx context.Context, tx *sql.Tx, event *events.Event, data T) error) projectionHandler {
+	return projectionHandler{
+		projection: projection,
+		handle: func(ctx context.Context, tx *sql.Tx, event *events.Event) error {
+			data, err := events.Decode[T](event)
+			if err != nil {
+				return err
+			}
+			return apply(ctx, tx, event, data)
+		},
+	}
+}
+
+// handlerFor returns the projection an event type updates and its handler,
+// or a nil handler for event types the projector does not use. Handlers
+// receive payloads upcast to the current schema version.
+func (p *Projector) handlerFor(eventType string) (string, eventHandler) {
+	handler, ok := p.handlers[eventType]
+	if !ok {
+		if !events.DefaultRegistry.IsRegistered(eventType) {
+			log.Printf("warning: skipping event of unknown type %q", eventType)
+		}
+		return "", nil
+	}
+
+	return handler.projection, func(ctx context.Context, tx *sql.Tx, event *events.Event) error {
+		upcast, err := p.upcasters.Upcast(event)
+		if err != nil {
+			return err
+		}
+		return handler.handle(ctx, tx, upcast)
+	}
+}
+
+// recordEvent processes an event until it is either applied or dead-lettered,
+// retrying with backoff while neither succeeds (e.g. while the database is
+// unreachable), so the checkpoint never moves past an event that is recorded
+// nowhere. It returns the error of an event that was dead-lettered, or
+// ctx.Err() if ctx is cancelled first.
+func (p *Projector) recordEvent(ctx context.Context, event *events.Event) error {
+	backoff := recordRetryBaseBackoff
+	for {
+		err := p.processEvent(ctx, event)
+		if !errors.Is(err, errEventNotRecorded) {
+			return err
+		}
+		log.Printf("failed to record event %s, retrying in %s [%s]: %v", event.ID, backoff, eventMetadata(event), err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > recordRetryMaxBackoff {
+			backoff = recordRetryMaxBackoff
+		}
+	}
 }
 
+// processEvent applies an event to the read models and advances the checkpoint
+// in the same transaction, so every event is applied exactly once. Events that
+// fail are dead-lettered so the projector can move past them. If an event can
+// be neither applied nor dead-lettered, the error wraps errEventNotRecorded.
 func (p *Projector) processEvent(ctx context.Context, event *events.Event) error {
 	start := time.Now()
 	
-	var err error
-	var projectionName string
+	projectionName, handle := p.handlerFor(event.Type)
 	
-	switch event.Type {
-	case events.StatusUpdateSubmitted:
-		projectionName = "status_updates"
-		err = p.handleStatusUpdateSubmitted(ctx, event)
-	case events.TeamRegistered:
-		projectionName = "teams"
-		err = p.handleTeamRegistered(ctx, event)
-	case events.TeamUpdated:
-		projectionName = "teams"
-		err = p.handleTeamUpdated(ctx, event)
-	default:
-		// Unknown event type, skip
+	err := p.inCheckpointTx(ctx, event, handle)
+	if handle == nil {
+		// Unknown event type: only the checkpoint moved
+		if err != nil {
+			return fmt.Errorf("%w: %v", errEventNotRecorded, err)
+		}
 		return nil
 	}
 	
@@ -106,6 +244,9 @@ func (p *Projector) processEvent(ctx context.Context, event *events.Event) error
 	
 	if err != nil {
 		recordProjectionError(projectionName)
+		if dlErr := p.deadLetter(ctx, event, err); dlErr != nil {
+			return fmt.Errorf("%w: %v (failed to dead-letter event: %v)", errEventNotRecorded, err, dlErr)
+		}
 		return err
 	}
 	
@@ -113,67 +254,263 @@ func (p *Projector) processEvent(ctx context.Context, event *events.Event) error
 	return nil
 }
 
-func (p *Projector) handleStatusUpdateSubmitted(ctx context.Context, event *events.Event) error {
-	var data events.StatusUpdateSubmittedData
-	if err := json.Unmarshal(event.Data, &data); err != nil {
-		return fmt.Errorf("failed to unmarshal event data: %w", err)
+// inCheckpointTx runs handle and records event.Position as the checkpoint in one
+// transaction. Events at or before the checkpoint were already applied and are skipped.
+func (p *Projector) inCheckpointTx(ctx context.Context, event *events.Event, handle eventHandler) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	position, err := p.lockCheckpoint(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if event.Position <= position {
+		return nil
+	}
+
+	if handle != nil {
+		if err := handle(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+
+	if err := p.saveCheckpoint(ctx, tx, event.Position); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	projectionCheckpointPosition.WithLabelValues(p.checkpoint).Set(float64(event.Position))
+	return nil
+}
+
+// lockCheckpoint reads the checkpoint and locks its row until tx ends, so
+// concurrent projectors cannot both apply the same event
+func (p *Projector) lockCheckpoint(ctx context.Context, tx *sql.Tx) (int64, error) {
+	var position int64
+	err := tx.QueryRowContext(ctx,
+		`SELECT position FROM checkpoints WHERE projection = $1 FOR UPDATE`,
+		p.checkpoint,
+	).Scan(&position)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to lock checkpoint: %w", err)
 	}
+	return position, nil
+}
 
+// saveCheckpoint records position as the last applied event within tx
+func (p *Projector) saveCheckpoint(ctx context.Context, tx *sql.Tx, position int64) error {
 	query := `
-		INSERT INTO status_updates (update_id, team_id, content, author, slack_user, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (update_id) DO NOTHING
+		INSERT INTO checkpoints (projection, position, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (projection) DO UPDATE
+		SET position = EXCLUDED.position, updated_at = EXCLUDED.updated_at
 	`
-	_, err := p.db.ExecContext(ctx, query,
+	if _, err := tx.ExecContext(ctx, query, p.checkpoint, position); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// loadCheckpoint returns the position of the last applied event (0 if none)
+func (p *Projector) loadCheckpoint(ctx context.Context) (int64, error) {
+	var position int64
+	err := p.db.QueryRowContext(ctx,
+		`SELECT position FROM checkpoints WHERE projection = $1`,
+		p.checkpoint,
+	).Scan(&position)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return position, err
+}
+
+func (p *Projector) handleStatusUpdateSubmitted(ctx context.Context, tx *sql.Tx, event *events.Event, data events.StatusUpdateSubmittedData) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (update_id, team_id, content, author, slack_user, created_at,
+			slack_channel, slack_ts, slack_thread_ts, slack_permalink,
+			format, done, next, blockers, health)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (update_id) DO NOTHING
+	`, p.table("status_updates"))
+	var sections events.UpdateSectionsData
+	if data.Sections != nil {
+		sections = *data.Sections
+	}
+	_, err := tx.ExecContext(ctx, query,
 		data.UpdateID,
 		data.TeamID,
 		data.Content,
 		data.Author,
 		data.SlackUser,
 		data.Timestamp,
+		nullString(data.SlackChannel),
+		nullString(data.SlackTS),
+		nullString(data.SlackThreadTS),
+		nullString(data.SlackPermalink),
+		data.Format,
+		nullString(sections.Done),
+		nullString(sections.Next),
+		nullString(sections.Blockers),
+		nullString(sections.Health),
 	)
-
 	return err
 }
 
-func (p *Projector) handleTeamRegistered(ctx context.Context, event *events.Event) error {
-	var data events.TeamRegisteredData
-	if err := json.Unmarshal(event.Data, &data); err != nil {
-		return fmt.Errorf("failed to unmarshal event data: %w", err)
+// nullString stores empty optional fields as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// handleStatusUpdateEdited records the content an edit replaced in the edit
+// history, then applies the edit. Structured updates are no longer edited, but
+// edits of them stored earlier made them free text.
+func (p *Projector) handleStatusUpdateEdited(ctx context.Context, tx *sql.Tx, event *events.Event, data events.StatusUpdateEditedData) error {
+	history := fmt.Sprintf(`
+		INSERT INTO %s (event_id, update_id, team_id, previous_content, content, edited_by, edited_at)
+		SELECT $1, update_id, team_id, content, $3, $4, $5
+		FROM %s
+		WHERE update_id = $2
+		ON CONFLICT (event_id) DO NOTHING
+	`, p.table("status_update_edits"), p.table("status_updates"))
+	if _, err := tx.ExecContext(ctx, history,
+		event.ID,
+		data.UpdateID,
+		data.Content,
+		data.EditedBy,
+		data.Timestamp,
+	); err != nil {
+		return err
 	}
 
-	query := `
-		INSERT INTO teams (team_id, name, slack_channel, created_at, updated_at)
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET content = $2, edited_at = $3,
+			format = 'text', done = NULL, next = NULL, blockers = NULL, health = NULL
+		WHERE update_id = $1
+	`, p.table("status_updates"))
+	result, err := tx.ExecContext(ctx, query, data.UpdateID, data.Content, data.Timestamp)
+	if err != nil {
+		return err
+	}
+	return requireUpdateRow(result, data.UpdateID)
+}
+
+// handleStatusUpdateDeleted removes the update, its edit history and its
+// comments from the read models; the events remain in the log
+func (p *Projector) handleStatusUpdateDeleted(ctx context.Context, tx *sql.Tx, event *events.Event, data events.StatusUpdateDeletedData) error {
+	for _, table := range []string{"status_update_edits", "update_comments"} {
+		query := fmt.Sprintf(`DELETE FROM %s WHERE update_id = $1`, p.table(table))
+		if _, err := tx.ExecContext(ctx, query, data.UpdateID); err != nil {
+			return err
+		}
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE update_id = $1`, p.table("status_updates"))
+	result, err := tx.ExecContext(ctx, query, data.UpdateID)
+	if err != nil {
+		return err
+	}
+	return requireUpdateRow(result, data.UpdateID)
+}
+
+// requireUpdateRow fails an edit or deletion that found no update to apply
+// to, which happens when the update's submission was dead-lettered or not yet
+// applied. The event is then dead-lettered and retried instead of being lost.
+func requireUpdateRow(result sql.Result, updateID string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("status update %s is not in the read model", updateID)
+	}
+	return nil
+}
+
+func (p *Projector) handleStatusUpdateCommented(ctx context.Context, tx *sql.Tx, event *events.Event, data events.StatusUpdateCommentedData) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (comment_id, update_id, team_id, content, author, slack_user, created_at,
+			slack_channel, slack_ts, slack_permalink)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (comment_id) DO NOTHING
+	`, p.table("update_comments"))
+	_, err := tx.ExecContext(ctx, query,
+		data.CommentID,
+		data.UpdateID,
+		data.TeamID,
+		data.Content,
+		data.Author,
+		nullString(data.SlackUser),
+		data.Timestamp,
+		nullString(data.SlackChannel),
+		nullString(data.SlackTS),
+		nullString(data.SlackPermalink),
+	)
+	return err
+}
+
+func (p *Projector) handleTeamRegistered(ctx context.Context, tx *sql.Tx, event *events.Event, data events.TeamRegisteredData) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (team_id, name, slack_channel, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $4)
 		ON CONFLICT (team_id) DO NOTHING
-	`
-	_, err := p.db.ExecContext(ctx, query,
+	`, p.table("teams"))
+	_, err := tx.ExecContext(ctx, query,
 		data.TeamID,
 		data.Name,
 		data.SlackChannel,
 		event.Timestamp,
 	)
-
 	return err
 }
 
-func (p *Projector) handleTeamUpdated(ctx context.Context, event *events.Event) error {
-	var data events.TeamUpdatedData
-	if err := json.Unmarshal(event.Data, &data); err != nil {
-		return fmt.Errorf("failed to unmarshal event data: %w", err)
-	}
-
-	query := `
-		UPDATE teams
+func (p *Projector) handleTeamUpdated(ctx context.Context, tx *sql.Tx, event *events.Event, data events.TeamUpdatedData) error {
+	query := fmt.Sprintf(`
+		UPDATE %s
 		SET name = $2, slack_channel = $3, updated_at = $4
 		WHERE team_id = $1
-	`
-	_, err := p.db.ExecContext(ctx, query,
+	`, p.table("teams"))
+	_, err := tx.ExecContext(ctx, query,
 		data.TeamID,
 		data.Name,
 		data.SlackChannel,
 		event.Timestamp,
 	)
+	return err
+}
+
+// handleTeamHealthChanged records the change in the team's health history and
+// makes it the team's current health
+func (p *Projector) handleTeamHealthChanged(ctx context.Context, tx *sql.Tx, event *events.Event, data events.TeamHealthChangedData) error {
+	history := fmt.Sprintf(`
+		INSERT INTO %s (event_id, team_id, health, previous_health, reason, changed_by, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (event_id) DO NOTHING
+	`, p.table("team_health_history"))
+	if _, err := tx.ExecContext(ctx, history,
+		event.ID,
+		data.TeamID,
+		data.Health,
+		nullString(data.PreviousHealth),
+		nullString(data.Reason),
+		nullString(data.ChangedBy),
+		data.Timestamp,
+	); err != nil {
+		return err
+	}
 
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET health = $2, health_reason = $3, health_changed_at = $4
+		WHERE team_id = $1
+	`, p.table("teams"))
+	_, err := tx.ExecContext(ctx, query, data.TeamID, data.Health, nullString(data.Reason), data.Timestamp)
 	return err
 }
@@ -2,6 +2,9 @@ package projections
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -48,7 +51,8 @@ func (e *projectorTestEnv) appendEvent(event *events.Event) {
 
 func (e *projectorTestEnv) rebuild() {
 	e.t.Helper()
-	testutil.AssertNoError(e.t, e.projector.rebuildProjections(e.ctx), "Rebuild projections")
+	_, err := e.projector.replay(e.ctx)
+	testutil.AssertNoError(e.t, err, "Rebuild projections")
 }
 
 // newTestEvent creates a test event with sensible defaults
@@ -97,7 +101,7 @@ func newStatusUpdateEvent(t *testing.T, teamID, content, author, slackUser strin
 		SlackUser: slackUser,
 		Timestamp: timestamp,
 	}
-	return newTestEvent(t, events.StatusUpdateSubmitted, teamID, data, timestamp)
+	return newTestEvent(t, events.StatusUpdateSubmitted, data.UpdateID, data, timestamp)
 }
 
 func TestProjector_RebuildProjections(t *testing.T) {
@@ -237,3 +241,444 @@ func TestProjector_RebuildProjections(t *testing.T) {
 		testutil.AssertEqual(t, team.SlackChannel, "#third", "SlackChannel")
 	})
 }
+
+func TestProjector_Start(t *testing.T) {
+	env := setupProjector(t)
+	ctx, cancel := context.WithCancel(env.ctx)
+	defer cancel()
+
+	teamID := "team-start"
+	now := time.Now()
+
+	// Written before the projector starts: picked up by the replay
+	env.appendEvent(newTeamRegisteredEvent(t, teamID, "Engineering", "#engineering", "", now))
+
+	testutil.AssertNoError(t, env.projector.Start(ctx), "Start")
+
+	// Written after the projector starts: picked up live
+	env.appendEvent(newStatusUpdateEvent(t, teamID, "Live update", "Alice", "alice", now.Add(time.Minute)))
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		updates, err := env.repo.GetTeamUpdates(env.ctx, teamID, 10)
+		if err == nil && len(updates) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Timeout waiting for live update to be projected (got %d updates, err %v)", len(updates), err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	team, err := env.repo.GetTeam(env.ctx, teamID)
+	testutil.AssertNoError(t, err, "GetTeam")
+	testutil.AssertEqual(t, team.Name, "Engineering", "Team name")
+}
+
+func TestProjector_Checkpoint(t *testing.T) {
+	env := setupProjector(t)
+	teamID := "team-checkpoint"
+	now := time.Now()
+
+	registered := newTeamRegisteredEvent(t, teamID, "Engineering", "#engineering", "", now)
+	env.appendEvent(registered)
+	updated := newTeamUpdatedEvent(t, teamID, "Platform", "#platform", "", now.Add(time.Minute))
+	env.appendEvent(updated)
+
+	env.rebuild()
+
+	position, err := env.projector.loadCheckpoint(env.ctx)
+	testutil.AssertNoError(t, err, "loadCheckpoint")
+	testutil.AssertEqual(t, position, updated.Position, "Checkpoint position")
+
+	// Change the read model behind the projector's back: if a restart re-applied
+	// events at or before the checkpoint, the name would be overwritten
+	_, err = env.testDB.DB.ExecContext(env.ctx, `UPDATE teams SET name = 'Untouched' WHERE team_id = $1`, teamID)
+	testutil.AssertNoError(t, err, "Update team name")
+
+	restarted := NewProjector(env.store, env.testDB.DB)
+	_, err = restarted.replay(env.ctx)
+	testutil.AssertNoError(t, err, "Rebuild after restart")
+
+	team, err := env.repo.GetTeam(env.ctx, teamID)
+	testutil.AssertNoError(t, err, "GetTeam")
+	testutil.AssertEqual(t, team.Name, "Untouched", "Team name (events applied once)")
+}
+
+func TestProjector_Replay(t *testing.T) {
+	t.Run("streams the log in batches", func(t *testing.T) {
+		env := setupProjector(t)
+		env.projector.batchSize = 2
+		teamID := "team-batches"
+		now := time.Now()
+
+		env.appendEvent(newTeamRegisteredEvent(t, teamID, "Engineering", "#engineering", "", now))
+		var last *events.Event
+		for i := 0; i < 6; i++ {
+			last = newStatusUpdateEvent(t, teamID, "Update", "Alice", "alice", now.Add(time.Duration(i)*time.Minute))
+			env.appendEvent(last)
+		}
+
+		position, err := env.projector.replay(env.ctx)
+		testutil.AssertNoError(t, err, "replay")
+		testutil.AssertEqual(t, position, last.Position, "Replay position")
+
+		updates, err := env.repo.GetTeamUpdates(env.ctx, teamID, 100)
+		testutil.AssertNoError(t, err, "GetTeamUpdates")
+		testutil.AssertEqual(t, len(updates), 6, "Update count")
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		env := setupProjector(t)
+		env.appendEvent(newTeamRegisteredEvent(t, "team-cancel", "Engineering", "#engineering", "", time.Now()))
+
+		ctx, cancel := context.WithCancel(env.ctx)
+		cancel()
+
+		if _, err := env.projector.replay(ctx); err != context.Canceled {
+			t.Fatalf("replay() error = %v, want context.Canceled", err)
+		}
+
+		position, err := env.projector.loadCheckpoint(env.ctx)
+		testutil.AssertNoError(t, err, "loadCheckpoint")
+		testutil.AssertEqual(t, position, int64(0), "Checkpoint position")
+	})
+}
+
+func TestProjector_UpcastsOldPayloads(t *testing.T) {
+	env := setupProjector(t)
+	now := time.Now()
+
+	// A team.registered payload from before "name" was called "title"
+	old := newTestEvent(t, events.TeamRegistered, "team-upcast", map[string]string{
+		"team_id":       "team-upcast",
+		"title":         "Engineering",
+		"slack_channel": "#engineering",
+	}, now)
+	old.SchemaVersion = 1
+	env.appendEvent(old)
+
+	upcasters := events.NewUpcasters()
+	upcasters.Register(events.TeamRegistered, 1, func(data json.RawMessage) (json.RawMessage, error) {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+		payload["name"] = payload["title"]
+		delete(payload, "title")
+		return json.Marshal(payload)
+	})
+	env.projector.upcasters = upcasters
+
+	env.rebuild()
+
+	team, err := env.repo.GetTeam(env.ctx, "team-upcast")
+	testutil.AssertNoError(t, err, "GetTeam")
+	testutil.AssertEqual(t, team.Name, "Engineering", "Team name")
+}
+
+func TestProjector_ProjectsLegacyUpdateStreams(t *testing.T) {
+	env := setupProjector(t)
+	now := time.Now()
+
+	env.appendEvent(newTeamRegisteredEvent(t, "team-legacy", "Engineering", "#engineering", "", now))
+
+	// Before updates had their own streams they were appended to the team's
+	legacy := newStatusUpdateEvent(t, "team-legacy", "Legacy update", "Alice", "alice", now)
+	legacy.AggregateID = "team-legacy"
+	env.appendEvent(legacy)
+	env.appendEvent(newStatusUpdateEvent(t, "team-legacy", "New update", "Bob", "bob", now.Add(time.Second)))
+
+	env.rebuild()
+
+	updates, err := env.repo.GetTeamUpdates(env.ctx, "team-legacy", 10)
+	testutil.AssertNoError(t, err, "GetTeamUpdates")
+	testutil.AssertEqual(t, len(updates), 2, "Update count")
+}
+
+func TestProjector_EditsAndDeletes(t *testing.T) {
+	env := setupProjector(t)
+	now := time.Now()
+
+	env.appendEvent(newTeamRegisteredEvent(t, "team-1", "Engineering", "#engineering", "", now))
+	submitted := newStatusUpdateEvent(t, "team-1", "Shiped it", "Alice", "alice", now)
+	env.appendEvent(submitted)
+	kept := newStatusUpdateEvent(t, "team-1", "Kept", "Bob", "bob", now)
+	env.appendEvent(kept)
+
+	edited := events.StatusUpdateEditedData{
+		UpdateID:  submitted.AggregateID,
+		TeamID:    "team-1",
+		Content:   "Shipped it",
+		EditedBy:  "alice",
+		Timestamp: now.Add(time.Minute),
+	}
+	env.appendEvent(newTestEvent(t, events.StatusUpdateEdited, submitted.AggregateID, edited, edited.Timestamp))
+	env.rebuild()
+
+	update, err := env.repo.GetUpdate(env.ctx, "team-1", submitted.AggregateID)
+	testutil.AssertNoError(t, err, "GetUpdate")
+	testutil.AssertEqual(t, update.Content, "Shipped it", "Content")
+	if update.EditedAt == nil {
+		t.Error("EditedAt = nil after an edit")
+	}
+
+	edits, err := env.repo.GetUpdateEdits(env.ctx, "team-1", submitted.AggregateID)
+	testutil.AssertNoError(t, err, "GetUpdateEdits")
+	if len(edits) != 1 {
+		t.Fatalf("GetUpdateEdits() returned %d edits, want 1", len(edits))
+	}
+	testutil.AssertEqual(t, edits[0].PreviousContent, "Shiped it", "PreviousContent")
+	testutil.AssertEqual(t, edits[0].EditedBy, "alice", "EditedBy")
+
+	deleted := events.StatusUpdateDeletedData{
+		UpdateID:  submitted.AggregateID,
+		TeamID:    "team-1",
+		Timestamp: now.Add(2 * time.Minute),
+	}
+	env.appendEvent(newTestEvent(t, events.StatusUpdateDeleted, submitted.AggregateID, deleted, deleted.Timestamp))
+	env.rebuild()
+
+	updates, err := env.repo.GetTeamUpdates(env.ctx, "team-1", 10)
+	testutil.AssertNoError(t, err, "GetTeamUpdates")
+	if len(updates) != 1 || updates[0].UpdateID != kept.AggregateID {
+		t.Errorf("GetTeamUpdates() = %+v, want only the kept update", updates)
+	}
+	edits, err = env.repo.GetUpdateEdits(env.ctx, "team-1", submitted.AggregateID)
+	testutil.AssertNoError(t, err, "GetUpdateEdits")
+	testutil.AssertEqual(t, len(edits), 0, "Edits after delete")
+}
+
+func TestProjector_DeadLettersChangesToUnknownUpdates(t *testing.T) {
+	env := setupProjector(t)
+	now := time.Now()
+
+	env.appendEvent(newTeamRegisteredEvent(t, "team-1", "Engineering", "#engineering", "", now))
+
+	// The update's submission never reached the read model
+	updateID := testutil.GenerateID()
+	edited := events.StatusUpdateEditedData{
+		UpdateID:  updateID,
+		TeamID:    "team-1",
+		Content:   "Shipped it",
+		Timestamp: now,
+	}
+	edit := newTestEvent(t, events.StatusUpdateEdited, updateID, edited, edited.Timestamp)
+	env.appendEvent(edit)
+	deleted := events.StatusUpdateDeletedData{
+		UpdateID:  updateID,
+		TeamID:    "team-1",
+		Timestamp: now.Add(time.Minute),
+	}
+	deletion := newTestEvent(t, events.StatusUpdateDeleted, updateID, deleted, deleted.Timestamp)
+	env.appendEvent(deletion)
+	env.rebuild()
+
+	deadLetters, err := env.repo.GetDeadLetters(env.ctx, DeadLetterPending)
+	testutil.AssertNoError(t, err, "GetDeadLetters")
+	if len(deadLetters) != 2 {
+		t.Fatalf("GetDeadLetters() returned %d dead letters, want 2", len(deadLetters))
+	}
+	testutil.AssertEqual(t, deadLetters[0].EventID, edit.ID, "Dead-lettered edit")
+	testutil.AssertEqual(t, deadLetters[1].EventID, deletion.ID, "Dead-lettered deletion")
+}
+
+func TestProjector_LinksSlackMessages(t *testing.T) {
+	env := setupProjector(t)
+	now := time.Now()
+
+	env.appendEvent(newTeamRegisteredEvent(t, "C123", "Engineering", "C123", "", now))
+	env.appendEvent(newStatusUpdateEvent(t, "C123", "Posted through the API", "Alice", "alice", now))
+	posted := events.StatusUpdateSubmittedData{
+		UpdateID:       testutil.GenerateID(),
+		TeamID:         "C123",
+		Content:        "Posted in Slack",
+		Author:         "U123",
+		SlackUser:      "U123",
+		Timestamp:      now,
+		SlackChannel:   "C123",
+		SlackTS:        "1700000000.000200",
+		SlackThreadTS:  "1700000000.000100",
+		SlackPermalink: "https://example.slack.com/archives/C123/p1700000000000200",
+	}
+	env.appendEvent(newTestEvent(t, events.StatusUpdateSubmitted, posted.UpdateID, posted, now))
+	env.rebuild()
+
+	update, err := env.repo.GetUpdateBySlackTS(env.ctx, "C123", posted.SlackTS)
+	testutil.AssertNoError(t, err, "GetUpdateBySlackTS")
+	testutil.AssertEqual(t, update.UpdateID, posted.UpdateID, "UpdateID")
+	want := SlackMessageLink{Channel: "C123", TS: posted.SlackTS, ThreadTS: posted.SlackThreadTS, Permalink: posted.SlackPermalink}
+	if update.SlackMessage == nil || *update.SlackMessage != want {
+		t.Errorf("SlackMessage = %+v, want %+v", update.SlackMessage, want)
+	}
+
+	if _, err := env.repo.GetUpdateBySlackTS(env.ctx, "C123", "1700000000.000999"); err != sql.ErrNoRows {
+		t.Errorf("GetUpdateBySlackTS() error = %v, want sql.ErrNoRows", err)
+	}
+
+	updates, err := env.repo.GetTeamUpdates(env.ctx, "C123", 10)
+	testutil.AssertNoError(t, err, "GetTeamUpdates")
+	for _, update := range updates {
+		if update.UpdateID != posted.UpdateID && update.SlackMessage != nil {
+			t.Errorf("update %s has SlackMessage %+v, want none", update.UpdateID, update.SlackMessage)
+		}
+	}
+}
+
+func TestProjector_Comments(t *testing.T) {
+	env := setupProjector(t)
+	now := time.Now()
+
+	env.appendEvent(newTeamRegisteredEvent(t, "team-1", "Engineering", "#engineering", "", now))
+	submitted := newStatusUpdateEvent(t, "team-1", "Shipped it", "Alice", "alice", now)
+	env.appendEvent(submitted)
+
+	comment := events.StatusUpdateCommentedData{
+		CommentID:      testutil.GenerateID(),
+		UpdateID:       submitted.AggregateID,
+		TeamID:         "team-1",
+		Content:        "Does that unblock the release?",
+		Author:         "U456",
+		SlackUser:      "U456",
+		Timestamp:      now.Add(time.Minute),
+		SlackChannel:   "C123",
+		SlackTS:        "1700000000.000300",
+		SlackThreadTS:  "1700000000.000100",
+		SlackPermalink: "https://example.slack.com/archives/C123/p1700000000000300",
+	}
+	env.appendEvent(newTestEvent(t, events.StatusUpdateCommented, submitted.AggregateID, comment, comment.Timestamp))
+	env.rebuild()
+
+	comments, err := env.repo.GetUpdateComments(env.ctx, "team-1", submitted.AggregateID)
+	testutil.AssertNoError(t, err, "GetUpdateComments")
+	if len(comments) != 1 {
+		t.Fatalf("GetUpdateComments() returned %d comments, want 1", len(comments))
+	}
+	testutil.AssertEqual(t, comments[0].Content, comment.Content, "Content")
+	testutil.AssertEqual(t, comments[0].SlackUser, "U456", "SlackUser")
+	if comments[0].SlackMessage == nil || comments[0].SlackMessage.TS != comment.SlackTS {
+		t.Errorf("SlackMessage = %+v, want ts %s", comments[0].SlackMessage, comment.SlackTS)
+	}
+
+	deleted := events.StatusUpdateDeletedData{
+		UpdateID:  submitted.AggregateID,
+		TeamID:    "team-1",
+		Timestamp: now.Add(2 * time.Minute),
+	}
+	env.appendEvent(newTestEvent(t, events.StatusUpdateDeleted, submitted.AggregateID, deleted, deleted.Timestamp))
+	env.rebuild()
+
+	comments, err = env.repo.GetUpdateComments(env.ctx, "team-1", submitted.AggregateID)
+	testutil.AssertNoError(t, err, "GetUpdateComments")
+	testutil.AssertEqual(t, len(comments), 0, "Comments after delete")
+}
+
+func TestProjector_StructuredUpdates(t *testing.T) {
+	env := setupProjector(t)
+	now := time.Now()
+
+	env.appendEvent(newTeamRegisteredEvent(t, "team-1", "Engineering", "#engineering", "", now))
+	env.appendEvent(newTeamRegisteredEvent(t, "team-2", "Product", "#product", "", now))
+	env.appendEvent(newStatusUpdateEvent(t, "team-1", "Free text", "Alice", "alice", now))
+
+	structured := func(teamID, blockers string, timestamp time.Time) *events.Event {
+		data := events.StatusUpdateSubmittedData{
+			UpdateID:  testutil.GenerateID(),
+			TeamID:    teamID,
+			Content:   "Done: Shipped login",
+			Format:    events.UpdateFormatStructured,
+			Sections:  &events.UpdateSectionsData{Done: "Shipped login", Blockers: blockers, Health: "amber"},
+			Author:    "Bob",
+			SlackUser: "bob",
+			Timestamp: timestamp,
+		}
+		event := newTestEvent(t, events.StatusUpdateSubmitted, data.UpdateID, data, timestamp)
+		event.SchemaVersion = 2
+		return event
+	}
+	blocked := structured("team-1", "Waiting on API keys", now.Add(time.Minute))
+	env.appendEvent(blocked)
+	env.appendEvent(structured("team-2", "", now.Add(2*time.Minute)))
+	env.appendEvent(structured("team-2", "Design review pending", now.Add(3*time.Minute)))
+	env.rebuild()
+
+	updates, err := env.repo.GetBlockedUpdates(env.ctx, 10)
+	testutil.AssertNoError(t, err, "GetBlockedUpdates")
+	if len(updates) != 2 {
+		t.Fatalf("GetBlockedUpdates() returned %d updates, want 2", len(updates))
+	}
+	testutil.AssertEqual(t, updates[0].TeamID, "team-2", "newest blocked team")
+	testutil.AssertEqual(t, updates[1].Format, events.UpdateFormatStructured, "Format")
+	if updates[1].Sections == nil || updates[1].Sections.Blockers != "Waiting on API keys" || updates[1].Sections.Health != "amber" {
+		t.Errorf("Sections = %+v, want the blocked sections", updates[1].Sections)
+	}
+
+	// Editing a structured update makes it free text
+	edited := events.StatusUpdateEditedData{
+		UpdateID:  blocked.AggregateID,
+		TeamID:    "team-1",
+		Content:   "Unblocked, shipped login",
+		Timestamp: now.Add(4 * time.Minute),
+	}
+	env.appendEvent(newTestEvent(t, events.StatusUpdateEdited, blocked.AggregateID, edited, edited.Timestamp))
+	env.rebuild()
+
+	update, err := env.repo.GetUpdate(env.ctx, "team-1", blocked.AggregateID)
+	testutil.AssertNoError(t, err, "GetUpdate")
+	testutil.AssertEqual(t, update.Format, events.UpdateFormatText, "Format after edit")
+	if update.Sections != nil {
+		t.Errorf("Sections after edit = %+v, want nil", update.Sections)
+	}
+	updates, err = env.repo.GetBlockedUpdates(env.ctx, 10)
+	testutil.AssertNoError(t, err, "GetBlockedUpdates")
+	testutil.AssertEqual(t, len(updates), 1, "blocked updates after edit")
+}
+
+func TestProjector_TeamHealth(t *testing.T) {
+	env := setupProjector(t)
+	now := time.Now()
+
+	env.appendEvent(newTeamRegisteredEvent(t, "team-1", "Engineering", "#engineering", "", now))
+	env.appendEvent(newTeamRegisteredEvent(t, "team-2", "Product", "#product", "", now))
+	env.appendEvent(newTeamRegisteredEvent(t, "team-3", "Design", "#design", "", now))
+
+	changed := func(teamID, health, previous, reason string, timestamp time.Time) *events.Event {
+		data := events.TeamHealthChangedData{
+			TeamID:         teamID,
+			Health:         health,
+			PreviousHealth: previous,
+			Reason:         reason,
+			ChangedBy:      "U123",
+			Timestamp:      timestamp,
+		}
+		return newTestEvent(t, events.TeamHealthChanged, teamID, data, timestamp)
+	}
+	env.appendEvent(changed("team-1", "amber", "", "Waiting on the vendor", now.Add(time.Minute)))
+	env.appendEvent(changed("team-1", "red", "amber", "Vendor missed the deadline", now.Add(2*time.Minute)))
+	env.appendEvent(changed("team-2", "green", "", "", now.Add(time.Minute)))
+	env.rebuild()
+
+	team, err := env.repo.GetTeam(env.ctx, "team-1")
+	testutil.AssertNoError(t, err, "GetTeam")
+	testutil.AssertEqual(t, team.Health, "red", "Health")
+	testutil.AssertEqual(t, team.HealthReason, "Vendor missed the deadline", "HealthReason")
+
+	history, err := env.repo.GetTeamHealthHistory(env.ctx, "team-1")
+	testutil.AssertNoError(t, err, "GetTeamHealthHistory")
+	if len(history) != 2 {
+		t.Fatalf("GetTeamHealthHistory() returned %d changes, want 2", len(history))
+	}
+	testutil.AssertEqual(t, history[0].Health, "amber", "history[0].Health")
+	testutil.AssertEqual(t, history[1].PreviousHealth, "amber", "history[1].PreviousHealth")
+	testutil.AssertEqual(t, history[1].ChangedBy, "U123", "history[1].ChangedBy")
+
+	teams, err := env.repo.GetTeamsByHealth(env.ctx)
+	testutil.AssertNoError(t, err, "GetTeamsByHealth")
+	var order []string
+	for _, team := range teams {
+		order = append(order, team.TeamID)
+	}
+	if strings.Join(order, ",") != "team-1,team-2,team-3" {
+		t.Errorf("GetTeamsByHealth() order = %v, want red, green, then unrated", order)
+	}
+}
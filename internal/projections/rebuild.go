@@ -0,0 +1,396 @@
+package projections
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/status-app/internal/events"
+)
+
+const (
+	// shadowSuffix is appended to read model table names while they are rebuilt
+	shadowSuffix = "_rebuild"
+
+	// Rebuild states
+	RebuildRunning   = "running"
+	RebuildSucceeded = "succeeded"
+	RebuildFailed    = "failed"
+)
+
+var (
+	// ErrRebuildInProgress is returned when a rebuild is requested while one is running
+	ErrRebuildInProgress = errors.New("projection rebuild already in progress")
+
+	// ErrRebuildVerification is returned when the shadow tables look incomplete,
+	// in which case the live tables are left untouched
+	ErrRebuildVerification = errors.New("rebuilt projections failed verification")
+)
+
+// projectionTable describes a read model table rebuilt into a shadow copy
+type projectionTable struct {
+	name string
+	// foreignKeys reference other projection tables, which are rebuilt as well
+	foreignKeys []foreignKey
+}
+
+type foreignKey struct {
+	column string
+	table  string
+}
+
+// projectionTables lists the read model tables, referenced tables first
+var projectionTables = []projectionTable{
+	{name: "teams"},
+	{name: "status_updates", foreignKeys: []foreignKey{{column: "team_id", table: "teams"}}},
+	{name: "status_update_edits", foreignKeys: []foreignKey{{column: "update_id", table: "status_updates"}}},
+	{name: "update_comments", foreignKeys: []foreignKey{{column: "update_id", table: "status_updates"}}},
+	{name: "team_health_history", foreignKeys: []foreignKey{{column: "team_id", table: "teams"}}},
+}
+
+// RebuildStatus reports the progress of the most recent rebuild
+type RebuildStatus struct {
+	State       string           `json:"state"`
+	StartedAt   time.Time        `json:"started_at"`
+	FinishedAt  *time.Time       `json:"finished_at,omitempty"`
+	Position    int64            `json:"position"`
+	LiveRows    map[string]int64 `json:"live_rows,omitempty"`
+	RebuiltRows map[string]int64 `json:"rebuilt_rows,omitempty"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// Rebuilder rebuilds the read models without downtime: it replays the event log
+// into shadow tables while the live tables keep serving reads, then swaps them
+// in atomically once the shadow tables have caught up
+type Rebuilder struct {
+	eventStore events.Store
+	db         *sql.DB
+
+	mu      sync.Mutex
+	running bool
+	status  *RebuildStatus
+}
+
+func NewRebuilder(eventStore events.Store, db *sql.DB) *Rebuilder {
+	return &Rebuilder{
+		eventStore: eventStore,
+		db:         db,
+	}
+}
+
+// Status returns the most recent rebuild status, or nil if none has run
+func (r *Rebuilder) Status() *RebuildStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.status == nil {
+		return nil
+	}
+	status := *r.status
+	return &status
+}
+
+// Begin marks a rebuild as running, returning ErrRebuildInProgress if one
+// already is. Callers that get nil must call Rebuild next.
+func (r *Rebuilder) Begin() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		return ErrRebuildInProgress
+	}
+	r.running = true
+	r.status = &RebuildStatus{State: RebuildRunning, StartedAt: time.Now()}
+	return nil
+}
+
+// Rebuild runs a rebuild started with Begin and records its outcome
+func (r *Rebuilder) Rebuild(ctx context.Context) error {
+	err := r.rebuild(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.running = false
+	r.status.FinishedAt = &now
+	if err != nil {
+		r.status.State = RebuildFailed
+		r.status.Error = err.Error()
+		return err
+	}
+	r.status.State = RebuildSucceeded
+	return nil
+}
+
+func (r *Rebuilder) rebuild(ctx context.Context) error {
+	live := NewProjector(r.eventStore, r.db)
+	shadow := NewProjector(r.eventStore, r.db)
+	shadow.checkpoint = defaultCheckpoint + shadowSuffix
+	shadow.tableSuffix = shadowSuffix
+
+	if err := r.createShadowTables(ctx, shadow); err != nil {
+		return fmt.Errorf("failed to create shadow tables: %w", err)
+	}
+
+	// Bulk of the work happens while the live tables keep serving
+	position, err := shadow.replay(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to replay events into shadow tables: %w", err)
+	}
+	r.setPosition(position)
+
+	if err := r.verify(ctx, live, shadow); err != nil {
+		return err
+	}
+
+	return r.swap(ctx, live, shadow)
+}
+
+// createShadowTables recreates empty shadow copies of the read model tables and
+// resets the shadow checkpoint so the replay starts from the beginning of the log
+func (r *Rebuilder) createShadowTables(ctx context.Context, shadow *Projector) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Drop dependents first in case a previous rebuild was interrupted
+	for i := len(projectionTables) - 1; i >= 0; i-- {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, shadow.table(projectionTables[i].name))); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range projectionTables {
+		query := fmt.Sprintf(`CREATE TABLE %s (LIKE %s INCLUDING ALL)`, shadow.table(t.name), t.name)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return err
+		}
+		// LIKE does not copy foreign keys; point them at the shadow tables
+		for _, fk := range t.foreignKeys {
+			query := fmt.Sprintf(`ALTER TABLE %s ADD FOREIGN KEY (%s) REFERENCES %s(%s)`,
+				shadow.table(t.name), fk.column, shadow.table(fk.table), fk.column)
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM checkpoints WHERE projection = $1`, shadow.checkpoint); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM dead_letters WHERE projection = $1`, shadow.checkpoint); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// verify compares row counts between the live and shadow tables. The log is
+// append-only, so a complete rebuild never has fewer rows than the live tables.
+func (r *Rebuilder) verify(ctx context.Context, live, shadow *Projector) error {
+	liveRows, err := countRows(ctx, r.db, live)
+	if err != nil {
+		return fmt.Errorf("failed to count live rows: %w", err)
+	}
+	rebuiltRows, err := countRows(ctx, r.db, shadow)
+	if err != nil {
+		return fmt.Errorf("failed to count rebuilt rows: %w", err)
+	}
+
+	r.mu.Lock()
+	r.status.LiveRows = liveRows
+	r.status.RebuiltRows = rebuiltRows
+	r.mu.Unlock()
+
+	for _, t := range projectionTables {
+		if rebuiltRows[t.name] < liveRows[t.name] {
+			return fmt.Errorf("%w: %s has %d rows, live table has %d",
+				ErrRebuildVerification, t.name, rebuiltRows[t.name], liveRows[t.name])
+		}
+	}
+	return nil
+}
+
+// swap replaces the live tables with the shadow tables in one transaction.
+// Holding the live checkpoint lock pauses the live projector while the shadow
+// tables apply the last events, so no event is lost or applied twice, and
+// readers see either the old tables or the new ones.
+func (r *Rebuilder) swap(ctx context.Context, live, shadow *Projector) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Make sure the live checkpoint row exists so there is something to lock
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO checkpoints (projection, position, updated_at) VALUES ($1, 0, NOW()) ON CONFLICT (projection) DO NOTHING`,
+		live.checkpoint,
+	); err != nil {
+		return fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+	if _, err := live.lockCheckpoint(ctx, tx); err != nil {
+		return err
+	}
+
+	// Apply events appended since the replay; the shadow projector uses its own
+	// checkpoint row, so it is not blocked by the lock held above
+	position, err := shadow.replay(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to catch up shadow tables: %w", err)
+	}
+
+	// Match the shadow indexes to the live ones while both tables exist
+	indexNames, err := shadowIndexNames(ctx, tx, shadow)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range projectionTables {
+		query := fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, t.name, t.name+"_old")
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to retire %s: %w", t.name, err)
+		}
+		query = fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, shadow.table(t.name), t.name)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to promote %s: %w", shadow.table(t.name), err)
+		}
+	}
+
+	for i := len(projectionTables) - 1; i >= 0; i-- {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DROP TABLE %s`, projectionTables[i].name+"_old")); err != nil {
+			return err
+		}
+	}
+
+	// Index names are schema-wide; give the promoted indexes the names of the
+	// dropped ones, which frees the shadow names for the next rebuild
+	for shadowName, liveName := range indexNames {
+		if shadowName == liveName {
+			continue
+		}
+		query := fmt.Sprintf(`ALTER INDEX %s RENAME TO %s`, shadowName, liveName)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to rename index %s: %w", shadowName, err)
+		}
+	}
+
+	if err := live.saveCheckpoint(ctx, tx, position); err != nil {
+		return err
+	}
+
+	// Dead letters recorded during the rebuild now belong to the live tables
+	if _, err := tx.ExecContext(ctx, `DELETE FROM dead_letters WHERE projection = $1`, live.checkpoint); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE dead_letters SET projection = $1 WHERE projection = $2`, live.checkpoint, shadow.checkpoint); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM checkpoints WHERE projection = $1`, shadow.checkpoint); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit swap: %w", err)
+	}
+
+	r.setPosition(position)
+	projectionCheckpointPosition.WithLabelValues(live.checkpoint).Set(float64(position))
+	log.Printf("projection rebuild swapped in at position %d", position)
+	return nil
+}
+
+func (r *Rebuilder) setPosition(position int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status.Position = position
+}
+
+// countRows returns the number of rows in each read model table p writes to
+func countRows(ctx context.Context, db *sql.DB, p *Projector) (map[string]int64, error) {
+	counts := make(map[string]int64, len(projectionTables))
+	for _, t := range projectionTables {
+		var count int64
+		if err := db.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s`, p.table(t.name))).Scan(&count); err != nil {
+			return nil, err
+		}
+		counts[t.name] = count
+	}
+	return counts, nil
+}
+
+// shadowIndexNames maps the name of each index on the shadow tables to the name
+// of the live index with the same definition, so the promoted tables keep the
+// index names the migrations created. Indexes with no live match fall back to
+// their name without the shadow suffix.
+func shadowIndexNames(ctx context.Context, tx *sql.Tx, shadow *Projector) (map[string]string, error) {
+	names := make(map[string]string)
+	for _, t := range projectionTables {
+		liveIndexes, err := listIndexes(ctx, tx, t.name)
+		if err != nil {
+			return nil, err
+		}
+		shadowIndexes, err := listIndexes(ctx, tx, shadow.table(t.name))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, shadowIndex := range shadowIndexes {
+			names[shadowIndex.name] = strings.Replace(shadowIndex.name, shadowSuffix, "", 1)
+			for i, liveIndex := range liveIndexes {
+				if liveIndex.definition == shadowIndex.definition {
+					names[shadowIndex.name] = liveIndex.name
+					liveIndexes = append(liveIndexes[:i], liveIndexes[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+// tableIndex is an index with its definition stripped of the index and table
+// names, so copies of an index on different tables compare equal
+type tableIndex struct {
+	name       string
+	definition string
+}
+
+// listIndexes returns the indexes of table in name order
+func listIndexes(ctx context.Context, tx *sql.Tx, table string) ([]tableIndex, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT indexname, indexdef FROM pg_indexes
+		WHERE schemaname = current_schema() AND tablename = $1
+		ORDER BY indexname
+	`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var indexes []tableIndex
+	for rows.Next() {
+		var index tableIndex
+		var definition string
+		if err := rows.Scan(&index.name, &definition); err != nil {
+			return nil, err
+		}
+		// "CREATE [UNIQUE] INDEX name ON table USING method (columns) ..."
+		if strings.HasPrefix(definition, "CREATE UNIQUE") {
+			index.definition = "UNIQUE"
+		}
+		if i := strings.Index(definition, " USING "); i >= 0 {
+			index.definition += definition[i:]
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, rows.Err()
+}
@@ -0,0 +1,123 @@
+package projections
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRebuilder_Begin(t *testing.T) {
+	rebuilder := NewRebuilder(nil, nil)
+
+	if status := rebuilder.Status(); status != nil {
+		t.Fatalf("expected no status before first rebuild, got %+v", status)
+	}
+
+	if err := rebuilder.Begin(); err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if err := rebuilder.Begin(); !errors.Is(err, ErrRebuildInProgress) {
+		t.Fatalf("second Begin() error = %v, want ErrRebuildInProgress", err)
+	}
+	if state := rebuilder.Status().State; state != RebuildRunning {
+		t.Errorf("state = %q, want %q", state, RebuildRunning)
+	}
+}
+
+func TestRebuilder_Rebuild(t *testing.T) {
+	t.Run("swaps rebuilt tables in and keeps projecting", func(t *testing.T) {
+		env := setupProjector(t)
+		now := time.Now()
+
+		env.appendEvent(newTeamRegisteredEvent(t, "team-rebuild", "Engineering", "#engineering", "", now))
+		env.appendEvent(newStatusUpdateEvent(t, "team-rebuild", "Shipped it", "Alice", "alice", now))
+		env.rebuild()
+
+		// Simulate a projection bug that corrupted the live read model
+		if _, err := env.testDB.DB.Exec(`UPDATE teams SET name = 'corrupted' WHERE team_id = 'team-rebuild'`); err != nil {
+			t.Fatalf("failed to corrupt team: %v", err)
+		}
+
+		indexes := indexNames(t, env.ctx, env.testDB.DB)
+
+		rebuilder := NewRebuilder(env.store, env.testDB.DB)
+		for i := 0; i < 2; i++ {
+			// A second rebuild must not collide with names left by the first
+			if err := rebuilder.Begin(); err != nil {
+				t.Fatalf("Begin() error = %v", err)
+			}
+			if err := rebuilder.Rebuild(env.ctx); err != nil {
+				t.Fatalf("Rebuild() error = %v", err)
+			}
+
+			// Migrations drop indexes by name, so the swap must keep them
+			if got := indexNames(t, env.ctx, env.testDB.DB); !reflect.DeepEqual(got, indexes) {
+				t.Errorf("index names after rebuild %d = %v, want %v", i+1, got, indexes)
+			}
+		}
+
+		status := rebuilder.Status()
+		if status.State != RebuildSucceeded {
+			t.Errorf("state = %q, want %q", status.State, RebuildSucceeded)
+		}
+		if status.RebuiltRows["status_updates"] != 1 {
+			t.Errorf("rebuilt status_updates = %d, want 1", status.RebuiltRows["status_updates"])
+		}
+
+		team, err := env.repo.GetTeam(env.ctx, "team-rebuild")
+		if err != nil {
+			t.Fatalf("GetTeam() error = %v", err)
+		}
+		if team.Name != "Engineering" {
+			t.Errorf("team name = %q, want %q", team.Name, "Engineering")
+		}
+
+		// The live checkpoint was moved to the swap position, so only new events apply
+		checkpoint, err := env.projector.loadCheckpoint(env.ctx)
+		if err != nil {
+			t.Fatalf("loadCheckpoint() error = %v", err)
+		}
+		if checkpoint != status.Position {
+			t.Errorf("checkpoint = %d, want %d", checkpoint, status.Position)
+		}
+
+		env.appendEvent(newStatusUpdateEvent(t, "team-rebuild", "Next thing", "Alice", "alice", now.Add(time.Minute)))
+		env.rebuild()
+
+		updates, err := env.repo.GetTeamUpdates(env.ctx, "team-rebuild", 100)
+		if err != nil {
+			t.Fatalf("GetTeamUpdates() error = %v", err)
+		}
+		if len(updates) != 2 {
+			t.Errorf("updates = %d, want 2", len(updates))
+		}
+	})
+}
+
+// indexNames lists the index names of each read model table
+func indexNames(t *testing.T, ctx context.Context, db *sql.DB) map[string][]string {
+	t.Helper()
+	names := make(map[string][]string)
+	for _, table := range projectionTables {
+		rows, err := db.QueryContext(ctx, `
+			SELECT indexname FROM pg_indexes
+			WHERE schemaname = current_schema() AND tablename = $1
+			ORDER BY indexname
+		`, table.name)
+		if err != nil {
+			t.Fatalf("failed to list indexes of %s: %v", table.name, err)
+		}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				t.Fatalf("failed to scan index name: %v", err)
+			}
+			names[table.name] = append(names[table.name], name)
+		}
+		rows.Close()
+	}
+	return names
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+
+	"github.com/yourusername/status-app/internal/events"
 )
 
 // Repository provides read access to projections
@@ -15,26 +17,43 @@ func NewRepository(db *sql.DB) *Repository {
 	return &Repository{db: db}
 }
 
+// teamColumns are the teams columns scanTeam reads
+const teamColumns = `team_id, name, slack_channel, created_at, updated_at,
+	health, health_reason, health_changed_at`
+
 // scanTeam scans a Team from a row scanner
 func (r *Repository) scanTeam(scanner interface {
 	Scan(...interface{}) error
 }) (*Team, error) {
 	var team Team
+	var health, reason sql.NullString
 	err := scanner.Scan(
 		&team.TeamID,
 		&team.Name,
 		&team.SlackChannel,
 		&team.CreatedAt,
 		&team.UpdatedAt,
+		&health,
+		&reason,
+		&team.HealthChangedAt,
 	)
+	team.Health = health.String
+	team.HealthReason = reason.String
 	return &team, err
 }
 
+// statusUpdateColumns are the status_updates columns scanStatusUpdate reads
+const statusUpdateColumns = `update_id, team_id, content, author, slack_user, created_at, edited_at,
+	slack_channel, slack_ts, slack_thread_ts, slack_permalink,
+	format, done, next, blockers, health`
+
 // scanStatusUpdate scans a StatusUpdate from a row scanner
 func (r *Repository) scanStatusUpdate(scanner interface {
 	Scan(...interface{}) error
 }) (*StatusUpdate, error) {
 	var update StatusUpdate
+	var channel, ts, threadTS, permalink sql.NullString
+	var done, next, blockers, health sql.NullString
 	err := scanner.Scan(
 		&update.UpdateID,
 		&update.TeamID,
@@ -42,13 +61,42 @@ func (r *Repository) scanStatusUpdate(scanner interface {
 		&update.Author,
 		&update.SlackUser,
 		&update.CreatedAt,
+		&update.EditedAt,
+		&channel,
+		&ts,
+		&threadTS,
+		&permalink,
+		&update.Format,
+		&done,
+		&next,
+		&blockers,
+		&health,
 	)
-	return &update, err
+	if err != nil {
+		return &update, err
+	}
+	if ts.Valid {
+		update.SlackMessage = &SlackMessageLink{
+			Channel:   channel.String,
+			TS:        ts.String,
+			ThreadTS:  threadTS.String,
+			Permalink: permalink.String,
+		}
+	}
+	if update.Format == events.UpdateFormatStructured {
+		update.Sections = &UpdateSections{
+			Done:     done.String,
+			Next:     next.String,
+			Blockers: blockers.String,
+			Health:   health.String,
+		}
+	}
+	return &update, nil
 }
 
 func (r *Repository) GetTeam(ctx context.Context, teamID string) (*Team, error) {
 	query := `
-		SELECT team_id, name, slack_channel, created_at, updated_at
+		SELECT ` + teamColumns + `
 		FROM teams
 		WHERE team_id = $1
 	`
@@ -57,7 +105,7 @@ func (r *Repository) GetTeam(ctx context.Context, teamID string) (*Team, error)
 
 func (r *Repository) GetAllTeams(ctx context.Context) ([]*Team, error) {
 	query := `
-		SELECT team_id, name, slack_channel, created_at, updated_at
+		SELECT ` + teamColumns + `
 		FROM teams
 		ORDER BY name
 	`
@@ -67,6 +115,31 @@ func (r *Repository) GetAllTeams(ctx context.Context) ([]*Team, error) {
 	}
 	defer rows.Close()
 
+	return r.scanTeams(rows)
+}
+
+// GetTeamsByHealth lists all teams, red first, then amber, green and teams
+// that were never rated, longest in their health first
+func (r *Repository) GetTeamsByHealth(ctx context.Context) ([]*Team, error) {
+	query := `
+		SELECT ` + teamColumns + `
+		FROM teams
+		ORDER BY
+			CASE health WHEN 'red' THEN 0 WHEN 'amber' THEN 1 WHEN 'green' THEN 2 ELSE 3 END,
+			health_changed_at ASC NULLS LAST,
+			name
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanTeams(rows)
+}
+
+// scanTeams scans multiple Team rows
+func (r *Repository) scanTeams(rows *sql.Rows) ([]*Team, error) {
 	var teams []*Team
 	for rows.Next() {
 		team, err := r.scanTeam(rows)
@@ -78,9 +151,60 @@ func (r *Repository) GetAllTeams(ctx context.Context) ([]*Team, error) {
 	return teams, rows.Err()
 }
 
+// GetTeamHealth returns the team's current health and its history, or
+// sql.ErrNoRows if the team does not exist
+func (r *Repository) GetTeamHealth(ctx context.Context, teamID string) (*TeamHealth, error) {
+	team, err := r.GetTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := r.GetTeamHealthHistory(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team health history: %w", err)
+	}
+
+	return &TeamHealth{
+		TeamID:    team.TeamID,
+		Health:    team.Health,
+		Reason:    team.HealthReason,
+		ChangedAt: team.HealthChangedAt,
+		History:   history,
+	}, nil
+}
+
+// GetTeamHealthHistory returns the changes of the team's health, oldest first
+func (r *Repository) GetTeamHealthHistory(ctx context.Context, teamID string) ([]*HealthChange, error) {
+	query := `
+		SELECT team_id, health, previous_health, reason, changed_by, changed_at
+		FROM team_health_history
+		WHERE team_id = $1
+		ORDER BY changed_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []*HealthChange{}
+	for rows.Next() {
+		var change HealthChange
+		var previous, reason, changedBy sql.NullString
+		if err := rows.Scan(&change.TeamID, &change.Health, &previous, &reason, &changedBy, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		change.PreviousHealth = previous.String
+		change.Reason = reason.String
+		change.ChangedBy = changedBy.String
+		history = append(history, &change)
+	}
+	return history, rows.Err()
+}
+
 func (r *Repository) GetTeamUpdates(ctx context.Context, teamID string, limit int) ([]*StatusUpdate, error) {
 	query := `
-		SELECT update_id, team_id, content, author, slack_user, created_at
+		SELECT ` + statusUpdateColumns + `
 		FROM status_updates
 		WHERE team_id = $1
 		ORDER BY created_at DESC
@@ -95,10 +219,117 @@ func (r *Repository) GetTeamUpdates(ctx context.Context, teamID string, limit in
 	return r.scanStatusUpdates(rows)
 }
 
+// GetUpdate returns one of the team's updates, or sql.ErrNoRows
+func (r *Repository) GetUpdate(ctx context.Context, teamID, updateID string) (*StatusUpdate, error) {
+	query := `
+		SELECT ` + statusUpdateColumns + `
+		FROM status_updates
+		WHERE team_id = $1 AND update_id = $2
+	`
+	return r.scanStatusUpdate(r.db.QueryRowContext(ctx, query, teamID, updateID))
+}
+
+// GetUpdateBySlackTS returns the team's update posted as the Slack message
+// with timestamp ts, or sql.ErrNoRows
+func (r *Repository) GetUpdateBySlackTS(ctx context.Context, teamID, ts string) (*StatusUpdate, error) {
+	query := `
+		SELECT ` + statusUpdateColumns + `
+		FROM status_updates
+		WHERE team_id = $1 AND slack_ts = $2
+	`
+	return r.scanStatusUpdate(r.db.QueryRowContext(ctx, query, teamID, ts))
+}
+
+// GetUpdateEdits returns the edit history of one of the team's updates, oldest first
+func (r *Repository) GetUpdateEdits(ctx context.Context, teamID, updateID string) ([]*UpdateEdit, error) {
+	query := `
+		SELECT update_id, previous_content, content, edited_by, edited_at
+		FROM status_update_edits
+		WHERE team_id = $1 AND update_id = $2
+		ORDER BY edited_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, teamID, updateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	edits := []*UpdateEdit{}
+	for rows.Next() {
+		var edit UpdateEdit
+		if err := rows.Scan(&edit.UpdateID, &edit.PreviousContent, &edit.Content, &edit.EditedBy, &edit.EditedAt); err != nil {
+			return nil, err
+		}
+		edits = append(edits, &edit)
+	}
+	return edits, rows.Err()
+}
+
+// GetUpdateComments returns the comments on one of the team's updates, oldest first
+func (r *Repository) GetUpdateComments(ctx context.Context, teamID, updateID string) ([]*UpdateComment, error) {
+	query := `
+		SELECT comment_id, update_id, team_id, content, author, slack_user, created_at,
+			slack_channel, slack_ts, slack_permalink
+		FROM update_comments
+		WHERE team_id = $1 AND update_id = $2
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, teamID, updateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*UpdateComment{}
+	for rows.Next() {
+		var comment UpdateComment
+		var slackUser, channel, ts, permalink sql.NullString
+		if err := rows.Scan(
+			&comment.CommentID,
+			&comment.UpdateID,
+			&comment.TeamID,
+			&comment.Content,
+			&comment.Author,
+			&slackUser,
+			&comment.CreatedAt,
+			&channel,
+			&ts,
+			&permalink,
+		); err != nil {
+			return nil, err
+		}
+		comment.SlackUser = slackUser.String
+		if ts.Valid {
+			comment.SlackMessage = &SlackMessageLink{Channel: channel.String, TS: ts.String, Permalink: permalink.String}
+		}
+		comments = append(comments, &comment)
+	}
+	return comments, rows.Err()
+}
+
 func (r *Repository) GetRecentUpdates(ctx context.Context, limit int) ([]*StatusUpdate, error) {
 	query := `
-		SELECT update_id, team_id, content, author, slack_user, created_at
+		SELECT ` + statusUpdateColumns + `
+		FROM status_updates
+		ORDER BY created_at DESC
+		LIMIT $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanStatusUpdates(rows)
+}
+
+// GetBlockedUpdates returns structured updates that report blockers, across
+// all teams, newest first
+func (r *Repository) GetBlockedUpdates(ctx context.Context, limit int) ([]*StatusUpdate, error) {
+	query := `
+		SELECT ` + statusUpdateColumns + `
 		FROM status_updates
+		WHERE blockers IS NOT NULL
 		ORDER BY created_at DESC
 		LIMIT $1
 	`
@@ -152,3 +383,41 @@ func (r *Repository) GetTeamSummary(ctx context.Context, teamID string) (*TeamSu
 
 	return &summary, nil
 }
+
+// GetDeadLetters lists dead letters in log order, optionally filtered by status
+func (r *Repository) GetDeadLetters(ctx context.Context, status string) ([]*DeadLetter, error) {
+	query := `
+		SELECT projection, event_id, position, event_type, error, attempts, status, next_attempt_at, created_at, updated_at
+		FROM dead_letters
+		WHERE $1::text = '' OR status = $1::text
+		ORDER BY position ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deadLetters []*DeadLetter
+	for rows.Next() {
+		var dl DeadLetter
+		err := rows.Scan(
+			&dl.Projection,
+			&dl.EventID,
+			&dl.Position,
+			&dl.EventType,
+			&dl.Error,
+			&dl.Attempts,
+			&dl.Status,
+			&dl.NextAttemptAt,
+			&dl.CreatedAt,
+			&dl.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, &dl)
+	}
+
+	return deadLetters, rows.Err()
+}
@@ -2,7 +2,10 @@ package projections
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/yourusername/status-app/tests/testutil"
 )
@@ -106,6 +109,32 @@ func TestRepository_GetTeamUpdates(t *testing.T) {
 	})
 }
 
+func TestRepository_GetUpdate(t *testing.T) {
+	ctx, repo, testDB := setupRepository(t)
+
+	testutil.InsertTestTeam(t, testDB.DB, "team-1", "Engineering", "#engineering")
+	testutil.InsertTestStatusUpdate(t, testDB.DB, "team-1", "Shipped it", "Author", "U123")
+
+	updates, err := repo.GetTeamUpdates(ctx, "team-1", 1)
+	testutil.AssertNoError(t, err, "GetTeamUpdates")
+	if len(updates) != 1 {
+		t.Fatalf("GetTeamUpdates() returned %d updates, want 1", len(updates))
+	}
+
+	t.Run("retrieves the team's update", func(t *testing.T) {
+		update, err := repo.GetUpdate(ctx, "team-1", updates[0].UpdateID)
+		testutil.AssertNoError(t, err, "GetUpdate")
+		testutil.AssertEqual(t, update.Content, "Shipped it", "Content")
+	})
+
+	t.Run("returns error for another team's update", func(t *testing.T) {
+		_, err := repo.GetUpdate(ctx, "team-2", updates[0].UpdateID)
+		if err != sql.ErrNoRows {
+			t.Errorf("GetUpdate() error = %v, want sql.ErrNoRows", err)
+		}
+	})
+}
+
 func TestRepository_GetRecentUpdates(t *testing.T) {
 	ctx, repo, testDB := setupRepository(t)
 
@@ -159,3 +188,53 @@ func TestRepository_GetTeamSummary(t *testing.T) {
 		t.Error("LastUpdateAt should not be zero")
 	}
 }
+
+func TestRepository_WaitForPosition(t *testing.T) {
+	ctx, repo, testDB := setupRepository(t)
+
+	setCheckpoint := func(position int64) {
+		_, err := testDB.DB.ExecContext(ctx, `
+			INSERT INTO checkpoints (projection, position, updated_at)
+			VALUES ($1, $2, NOW())
+			ON CONFLICT (projection) DO UPDATE SET position = EXCLUDED.position
+		`, defaultCheckpoint, position)
+		testutil.AssertNoError(t, err, "set checkpoint")
+	}
+
+	t.Run("returns once the checkpoint is reached", func(t *testing.T) {
+		setCheckpoint(5)
+		testutil.AssertNoError(t, repo.WaitForPosition(ctx, 5, time.Second), "WaitForPosition")
+	})
+
+	t.Run("times out behind the checkpoint", func(t *testing.T) {
+		err := repo.WaitForPosition(ctx, 6, 50*time.Millisecond)
+		if !errors.Is(err, ErrPositionNotReached) {
+			t.Errorf("WaitForPosition() error = %v, want ErrPositionNotReached", err)
+		}
+	})
+
+	t.Run("waits for the projector to catch up", func(t *testing.T) {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			setCheckpoint(7)
+		}()
+		testutil.AssertNoError(t, repo.WaitForPosition(ctx, 7, 5*time.Second), "WaitForPosition")
+	})
+
+	t.Run("fails past an open dead letter", func(t *testing.T) {
+		_, err := testDB.DB.ExecContext(ctx, `
+			INSERT INTO dead_letters (projection, event_id, position, event_type, error, attempts, status, next_attempt_at, created_at, updated_at)
+			VALUES ($1, 'event-6', 6, 'status_update.edited', 'boom', 1, $2, NOW(), NOW(), NOW())
+		`, defaultCheckpoint, DeadLetterPending)
+		testutil.AssertNoError(t, err, "insert dead letter")
+
+		testutil.AssertNoError(t, repo.WaitForPosition(ctx, 5, time.Second), "WaitForPosition below the dead letter")
+		if err := repo.WaitForPosition(ctx, 7, time.Second); !errors.Is(err, ErrPositionDeadLettered) {
+			t.Errorf("WaitForPosition() error = %v, want ErrPositionDeadLettered", err)
+		}
+
+		_, err = testDB.DB.ExecContext(ctx, `UPDATE dead_letters SET status = $1 WHERE event_id = 'event-6'`, DeadLetterSkipped)
+		testutil.AssertNoError(t, err, "skip dead letter")
+		testutil.AssertNoError(t, repo.WaitForPosition(ctx, 7, time.Second), "WaitForPosition after skipping")
+	})
+}
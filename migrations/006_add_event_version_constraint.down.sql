@@ -0,0 +1 @@
+ALTER TABLE events.events DROP CONSTRAINT IF EXISTS events_aggregate_version_unique;
@@ -0,0 +1,12 @@
+-- Renumber existing events so every aggregate has contiguous versions starting at 1
+UPDATE events.events e
+SET version = ordered.version
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY aggregate_id ORDER BY timestamp, created_at, id) AS version
+    FROM events.events
+) ordered
+WHERE e.id = ordered.id;
+
+-- Optimistic concurrency: only one event may claim a given aggregate version
+ALTER TABLE events.events
+    ADD CONSTRAINT events_aggregate_version_unique UNIQUE (aggregate_id, version);
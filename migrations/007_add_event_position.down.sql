@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS events.idx_events_position;
+ALTER TABLE events.events DROP COLUMN IF EXISTS position;
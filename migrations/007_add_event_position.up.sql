@@ -0,0 +1,14 @@
+-- Global, gap-free sequence number used to read the log by cursor
+ALTER TABLE events.events ADD COLUMN position BIGINT;
+
+UPDATE events.events e
+SET position = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY timestamp, created_at, id) AS position
+    FROM events.events
+) ordered
+WHERE e.id = ordered.id;
+
+ALTER TABLE events.events ALTER COLUMN position SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_events_position ON events.events(position);
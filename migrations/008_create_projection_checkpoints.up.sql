@@ -0,0 +1,7 @@
+-- Last event position applied to each projection, written in the same
+-- transaction as the read model changes
+CREATE TABLE IF NOT EXISTS projections.checkpoints (
+    projection VARCHAR(255) PRIMARY KEY,
+    position BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
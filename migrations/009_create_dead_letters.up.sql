@@ -0,0 +1,16 @@
+-- Events a projection failed to apply, retried with backoff or handled by an admin
+CREATE TABLE IF NOT EXISTS projections.dead_letters (
+    projection VARCHAR(255) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    position BIGINT NOT NULL,
+    event_type VARCHAR(255) NOT NULL,
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (projection, event_id)
+);
+
+CREATE INDEX idx_dead_letters_due ON projections.dead_letters(status, next_attempt_at);
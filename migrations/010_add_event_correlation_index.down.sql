@@ -0,0 +1 @@
+DROP INDEX IF EXISTS events.idx_events_correlation_id;
@@ -0,0 +1,2 @@
+-- Look up every event caused by one user action
+CREATE INDEX idx_events_correlation_id ON events.events((metadata->>'correlation_id'));
@@ -0,0 +1 @@
+ALTER TABLE events.events DROP COLUMN IF EXISTS schema_version;
@@ -0,0 +1,2 @@
+-- Schema version of each event's payload; existing events are at the initial version
+ALTER TABLE events.events ADD COLUMN schema_version INTEGER NOT NULL DEFAULT 1;
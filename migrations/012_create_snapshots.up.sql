@@ -0,0 +1,10 @@
+-- Latest snapshot of each aggregate's state, so rehydration only reads the
+-- events after it. Snapshots are a cache and can be truncated at any time.
+CREATE TABLE IF NOT EXISTS events.snapshots (
+    aggregate_id VARCHAR(255) PRIMARY KEY,
+    aggregate_type VARCHAR(255) NOT NULL,
+    version INTEGER NOT NULL,
+    schema_version INTEGER NOT NULL,
+    state JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
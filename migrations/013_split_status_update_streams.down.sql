@@ -0,0 +1,19 @@
+-- Move status updates back into their team's stream
+ALTER TABLE events.events DROP CONSTRAINT events_aggregate_version_unique;
+
+UPDATE events.events
+SET aggregate_id = data->>'team_id'
+WHERE type = 'status_update.submitted';
+
+UPDATE events.events e
+SET version = ordered.version
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY aggregate_id ORDER BY position) AS version
+    FROM events.events
+) ordered
+WHERE e.id = ordered.id AND e.version <> ordered.version;
+
+ALTER TABLE events.events
+    ADD CONSTRAINT events_aggregate_version_unique UNIQUE (aggregate_id, version);
+
+TRUNCATE events.snapshots;
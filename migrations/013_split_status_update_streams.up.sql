@@ -0,0 +1,23 @@
+-- Status updates become their own aggregates, keyed by update_id, so team
+-- streams only hold team events. Event positions do not change, so the
+-- projections and their checkpoints read the log exactly as before.
+ALTER TABLE events.events DROP CONSTRAINT events_aggregate_version_unique;
+
+UPDATE events.events
+SET aggregate_id = data->>'update_id'
+WHERE type = 'status_update.submitted';
+
+-- Renumber every stream so versions stay contiguous from 1
+UPDATE events.events e
+SET version = ordered.version
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY aggregate_id ORDER BY position) AS version
+    FROM events.events
+) ordered
+WHERE e.id = ordered.id AND e.version <> ordered.version;
+
+ALTER TABLE events.events
+    ADD CONSTRAINT events_aggregate_version_unique UNIQUE (aggregate_id, version);
+
+-- Snapshots were taken at the old team stream versions
+TRUNCATE events.snapshots;
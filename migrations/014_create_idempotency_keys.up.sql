@@ -0,0 +1,13 @@
+-- Responses to requests made with an Idempotency-Key, replayed to retries.
+-- status_code is NULL while the first request with the key is being handled.
+CREATE TABLE IF NOT EXISTS events.idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    fingerprint VARCHAR(64) NOT NULL,
+    status_code INTEGER,
+    headers JSONB,
+    body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON events.idempotency_keys(expires_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS projections.status_update_edits;
+ALTER TABLE projections.status_updates DROP COLUMN IF EXISTS edited_at;
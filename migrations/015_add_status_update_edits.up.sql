@@ -0,0 +1,15 @@
+-- Edits and deletions of status updates: updates remember when they were last
+-- edited, and every edit keeps the content it replaced
+ALTER TABLE projections.status_updates ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS projections.status_update_edits (
+    event_id VARCHAR(255) PRIMARY KEY,
+    update_id VARCHAR(255) NOT NULL REFERENCES projections.status_updates(update_id),
+    team_id VARCHAR(255) NOT NULL,
+    previous_content TEXT NOT NULL,
+    content TEXT NOT NULL,
+    edited_by VARCHAR(255) NOT NULL,
+    edited_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_status_update_edits_update ON projections.status_update_edits(update_id, edited_at);
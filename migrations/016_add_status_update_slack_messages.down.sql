@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS projections.idx_status_updates_slack_ts;
+ALTER TABLE projections.status_updates DROP COLUMN IF EXISTS slack_permalink;
+ALTER TABLE projections.status_updates DROP COLUMN IF EXISTS slack_thread_ts;
+ALTER TABLE projections.status_updates DROP COLUMN IF EXISTS slack_ts;
+ALTER TABLE projections.status_updates DROP COLUMN IF EXISTS slack_channel;
@@ -0,0 +1,9 @@
+-- The Slack message each status update was posted as, so updates link back to
+-- Slack and edits of a message can be matched to its update. Updates submitted
+-- through the API or before this migration have none.
+ALTER TABLE projections.status_updates ADD COLUMN IF NOT EXISTS slack_channel VARCHAR(255);
+ALTER TABLE projections.status_updates ADD COLUMN IF NOT EXISTS slack_ts VARCHAR(255);
+ALTER TABLE projections.status_updates ADD COLUMN IF NOT EXISTS slack_thread_ts VARCHAR(255);
+ALTER TABLE projections.status_updates ADD COLUMN IF NOT EXISTS slack_permalink TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_status_updates_slack_ts ON projections.status_updates(team_id, slack_ts);
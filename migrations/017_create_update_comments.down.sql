@@ -0,0 +1 @@
+DROP TABLE IF EXISTS projections.update_comments;
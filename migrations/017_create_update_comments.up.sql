@@ -0,0 +1,16 @@
+-- Comments on status updates, such as replies in the Slack thread under an
+-- update
+CREATE TABLE IF NOT EXISTS projections.update_comments (
+    comment_id VARCHAR(255) PRIMARY KEY,
+    update_id VARCHAR(255) NOT NULL REFERENCES projections.status_updates(update_id),
+    team_id VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    slack_user VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    slack_channel VARCHAR(255),
+    slack_ts VARCHAR(255),
+    slack_permalink TEXT
+);
+
+CREATE INDEX idx_update_comments_update ON projections.update_comments(update_id, created_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS projections.idx_status_updates_blocked;
+ALTER TABLE projections.status_updates DROP COLUMN IF EXISTS health;
+ALTER TABLE projections.status_updates DROP COLUMN IF EXISTS blockers;
+ALTER TABLE projections.status_updates DROP COLUMN IF EXISTS next;
+ALTER TABLE projections.status_updates DROP COLUMN IF EXISTS done;
+ALTER TABLE projections.status_updates DROP COLUMN IF EXISTS format;
@@ -0,0 +1,11 @@
+-- Structured status updates: done, next and blockers sections plus an
+-- optional health. Free-text updates, including every update from before this
+-- migration, have format 'text' and no sections.
+ALTER TABLE projections.status_updates ADD COLUMN IF NOT EXISTS format VARCHAR(16) NOT NULL DEFAULT 'text';
+ALTER TABLE projections.status_updates ADD COLUMN IF NOT EXISTS done TEXT;
+ALTER TABLE projections.status_updates ADD COLUMN IF NOT EXISTS next TEXT;
+ALTER TABLE projections.status_updates ADD COLUMN IF NOT EXISTS blockers TEXT;
+ALTER TABLE projections.status_updates ADD COLUMN IF NOT EXISTS health VARCHAR(16);
+
+-- Blocked updates are queried across teams, newest first
+CREATE INDEX IF NOT EXISTS idx_status_updates_blocked ON projections.status_updates(created_at DESC) WHERE blockers IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS projections.team_health_history;
+ALTER TABLE projections.teams DROP COLUMN IF EXISTS health_changed_at;
+ALTER TABLE projections.teams DROP COLUMN IF EXISTS health_reason;
+ALTER TABLE projections.teams DROP COLUMN IF EXISTS health;
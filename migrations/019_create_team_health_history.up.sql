@@ -0,0 +1,17 @@
+-- Team health: teams keep their current health, and every change is kept in
+-- the health history
+ALTER TABLE projections.teams ADD COLUMN IF NOT EXISTS health VARCHAR(16);
+ALTER TABLE projections.teams ADD COLUMN IF NOT EXISTS health_reason TEXT;
+ALTER TABLE projections.teams ADD COLUMN IF NOT EXISTS health_changed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS projections.team_health_history (
+    event_id VARCHAR(255) PRIMARY KEY,
+    team_id VARCHAR(255) NOT NULL REFERENCES projections.teams(team_id),
+    health VARCHAR(16) NOT NULL,
+    previous_health VARCHAR(16),
+    reason TEXT,
+    changed_by VARCHAR(255),
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_team_health_history_team ON projections.team_health_history(team_id, changed_at);
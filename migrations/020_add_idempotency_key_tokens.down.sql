@@ -0,0 +1 @@
+ALTER TABLE events.idempotency_keys DROP COLUMN IF EXISTS token;
@@ -0,0 +1,4 @@
+-- Each reservation of an idempotency key gets a token, so a request whose
+-- reservation timed out and was claimed again cannot complete or release the
+-- new one. Reservations made before this migration have no token and expire.
+ALTER TABLE events.idempotency_keys ADD COLUMN IF NOT EXISTS token VARCHAR(64);
@@ -18,7 +18,7 @@ func TestAutoRegisterTeamOnFirstUpdate(t *testing.T) {
 	defer testDB.Cleanup()
 
 	ctx := context.Background()
-	eventStore := newTestEventStore(testDB.DB)
+	eventStore := newTestEventStore(t, testDB)
 	cmdHandler := commands.NewHandler(eventStore)
 	repo := projections.NewRepository(testDB.DB)
 
@@ -33,7 +33,7 @@ func TestAutoRegisterTeamOnFirstUpdate(t *testing.T) {
 		"jane.smith",
 	)
 
-	err := cmdHandler.Handle(ctx, submitCmd)
+	_, err := cmdHandler.Handle(ctx, submitCmd)
 	if err != nil {
 		t.Fatalf("Failed to submit status update: %v", err)
 	}
@@ -110,7 +110,7 @@ func TestSubsequentUpdateDoesNotRegisterAgain(t *testing.T) {
 	defer testDB.Cleanup()
 
 	ctx := context.Background()
-	eventStore := newTestEventStore(testDB.DB)
+	eventStore := newTestEventStore(t, testDB)
 	cmdHandler := commands.NewHandler(eventStore)
 
 	channelID := "C987654321"
@@ -124,7 +124,7 @@ func TestSubsequentUpdateDoesNotRegisterAgain(t *testing.T) {
 		"alice",
 	)
 
-	err := cmdHandler.Handle(ctx, firstCmd)
+	_, err := cmdHandler.Handle(ctx, firstCmd)
 	if err != nil {
 		t.Fatalf("Failed to submit first update: %v", err)
 	}
@@ -137,7 +137,7 @@ func TestSubsequentUpdateDoesNotRegisterAgain(t *testing.T) {
 		"bob",
 	)
 
-	err = cmdHandler.Handle(ctx, secondCmd)
+	_, err = cmdHandler.Handle(ctx, secondCmd)
 	if err != nil {
 		t.Fatalf("Failed to submit second update: %v", err)
 	}
@@ -3,120 +3,25 @@ package e2e
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
+	"testing"
 	"time"
 
 	"github.com/yourusername/status-app/internal/commands"
 	"github.com/yourusername/status-app/internal/domain"
 	"github.com/yourusername/status-app/internal/events"
+	"github.com/yourusername/status-app/tests/testutil"
 )
 
-// testEventStore wraps sql.DB to implement events.Store for testing
-type testEventStore struct {
-	db *sql.DB
-}
-
-func newTestEventStore(db *sql.DB) *testEventStore {
-	return &testEventStore{db: db}
-}
-
-func (s *testEventStore) Append(ctx context.Context, event *events.Event) error {
-	query := `
-		INSERT INTO events (id, type, aggregate_id, data, timestamp, metadata, version, created_at)
-		VALUES ($1, $2, $3, $4::jsonb, $5, $6::jsonb, $7, $8)
-	`
-	
-	// Convert metadata to proper format
-	var metadata interface{}
-	if len(event.Metadata) > 0 {
-		metadata = string(event.Metadata)
-	}
-	
-	_, err := s.db.ExecContext(ctx, query,
-		event.ID,
-		event.Type,
-		event.AggregateID,
-		string(event.Data), // Convert []byte to string for JSONB
-		event.Timestamp,
-		metadata,
-		event.Version,
-		time.Now(),
-	)
-	return err
-}
-
-func (s *testEventStore) GetByAggregateID(ctx context.Context, aggregateID string) ([]*events.Event, error) {
-	query := `
-		SELECT id, type, aggregate_id, data, timestamp, metadata, version
-		FROM events
-		WHERE aggregate_id = $1
-		ORDER BY timestamp ASC
-	`
-	rows, err := s.db.QueryContext(ctx, query, aggregateID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return scanEvents(rows)
-}
-
-func (s *testEventStore) GetAll(ctx context.Context, eventType string, offset, limit int) ([]*events.Event, error) {
-	var query string
-	var args []interface{}
-
-	if eventType == "" {
-		query = `SELECT id, type, aggregate_id, data, timestamp, metadata, version
-				 FROM events ORDER BY timestamp ASC LIMIT $1 OFFSET $2`
-		args = []interface{}{limit, offset}
-	} else {
-		query = `SELECT id, type, aggregate_id, data, timestamp, metadata, version
-				 FROM events WHERE type = $1 ORDER BY timestamp ASC LIMIT $2 OFFSET $3`
-		args = []interface{}{eventType, limit, offset}
-	}
-
-	rows, err := s.db.QueryContext(ctx, query, args...)
+// newTestEventStore opens the real Postgres event store on the test database,
+// closing it when the test ends
+func newTestEventStore(t *testing.T, testDB *testutil.TestDB) *events.PostgresStore {
+	t.Helper()
+	store, err := events.NewPostgresStore(testDB.ConnectionString())
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return scanEvents(rows)
-}
-
-func (s *testEventStore) Subscribe(ctx context.Context, eventTypes []string) (<-chan *events.Event, error) {
-	ch := make(chan *events.Event)
-	close(ch)
-	return ch, nil
-}
-
-func (s *testEventStore) Close() error {
-	return nil
-}
-
-func scanEvents(rows *sql.Rows) ([]*events.Event, error) {
-	var result []*events.Event
-	for rows.Next() {
-		var event events.Event
-		var metadata sql.NullString
-		err := rows.Scan(
-			&event.ID,
-			&event.Type,
-			&event.AggregateID,
-			&event.Data,
-			&event.Timestamp,
-			&metadata,
-			&event.Version,
-		)
-		if err != nil {
-			return nil, err
-		}
-		if metadata.Valid {
-			event.Metadata = json.RawMessage(metadata.String)
-		}
-		result = append(result, &event)
+		t.Fatalf("Failed to create event store: %v", err)
 	}
-	return result, rows.Err()
+	t.Cleanup(func() { store.Close() })
+	return store
 }
 
 // Projection helpers
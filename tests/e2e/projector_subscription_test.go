@@ -19,20 +19,13 @@ func TestProjectorSubscriptionRealtime(t *testing.T) {
 	defer testDB.Cleanup()
 
 	ctx := context.Background()
-	
-	// Use real PostgresStore instead of testEventStore for LISTEN/NOTIFY support
-	eventStore, err := events.NewPostgresStore(testDB.ConnectionString())
-	if err != nil {
-		t.Fatalf("Failed to create event store: %v", err)
-	}
-	defer eventStore.Close()
-	
+	eventStore := newTestEventStore(t, testDB)
 	cmdHandler := commands.NewHandler(eventStore)
 	repo := projections.NewRepository(testDB.DB)
 
 	// Start the projector
 	projector := projections.NewProjector(eventStore, testDB.DB)
-	err = projector.Start(ctx)
+	err := projector.Start(ctx)
 	if err != nil {
 		t.Fatalf("Failed to start projector: %v", err)
 	}
@@ -42,7 +35,7 @@ func TestProjectorSubscriptionRealtime(t *testing.T) {
 
 	registerCmd := mustRegisterTeam("Engineering", "#engineering")
 
-	err = cmdHandler.Handle(ctx, registerCmd)
+	_, err = cmdHandler.Handle(ctx, registerCmd)
 	if err != nil {
 		t.Fatalf("Failed to register team: %v", err)
 	}
@@ -78,7 +71,7 @@ func TestProjectorSubscriptionRealtime(t *testing.T) {
 		"alice",
 	)
 
-	err = cmdHandler.Handle(ctx, submitCmd)
+	_, err = cmdHandler.Handle(ctx, submitCmd)
 	if err != nil {
 		t.Fatalf("Failed to submit status update: %v", err)
 	}
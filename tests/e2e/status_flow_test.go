@@ -18,13 +18,13 @@ func TestStatusUpdateFlow(t *testing.T) {
 	defer testDB.Cleanup()
 
 	ctx := context.Background()
-	eventStore := newTestEventStore(testDB.DB)
+	eventStore := newTestEventStore(t, testDB)
 	cmdHandler := commands.NewHandler(eventStore)
 	repo := projections.NewRepository(testDB.DB)
 
 	registerCmd := mustRegisterTeam("Engineering", "#engineering")
 
-	err := cmdHandler.Handle(ctx, registerCmd)
+	_, err := cmdHandler.Handle(ctx, registerCmd)
 	if err != nil {
 		t.Fatalf("Failed to register team: %v", err)
 	}
@@ -60,19 +60,19 @@ func TestStatusUpdateFlow(t *testing.T) {
 		"john.doe",
 	)
 
-	err = cmdHandler.Handle(ctx, submitCmd)
+	_, err = cmdHandler.Handle(ctx, submitCmd)
 	if err != nil {
 		t.Fatalf("Failed to submit status update: %v", err)
 	}
 
-	// Verify event was stored
+	// Verify the update was stored in its own stream, leaving the team's alone
 	teamEvents, err := eventStore.GetByAggregateID(ctx, teamID)
 	if err != nil {
 		t.Fatalf("Failed to get team events: %v", err)
 	}
 
-	if len(teamEvents) < 2 {
-		t.Fatalf("Expected at least 2 events, got %d", len(teamEvents))
+	if len(teamEvents) != 1 {
+		t.Fatalf("Expected only the team.registered event in the team stream, got %d", len(teamEvents))
 	}
 
 	// Project the status update
@@ -18,13 +18,13 @@ func TestTeamManagementFlow(t *testing.T) {
 	defer testDB.Cleanup()
 
 	ctx := context.Background()
-	eventStore := newTestEventStore(testDB.DB)
+	eventStore := newTestEventStore(t, testDB)
 	cmdHandler := commands.NewHandler(eventStore)
 	repo := projections.NewRepository(testDB.DB)
 
 	registerCmd := mustRegisterTeam("Product Team", "#product")
 
-	err := cmdHandler.Handle(ctx, registerCmd)
+	_, err := cmdHandler.Handle(ctx, registerCmd)
 	if err != nil {
 		t.Fatalf("Failed to register team: %v", err)
 	}
@@ -58,7 +58,7 @@ func TestTeamManagementFlow(t *testing.T) {
 
 	updateCmd := mustUpdateTeam(teamID, "Product Team v2", "#product-new")
 
-	err = cmdHandler.Handle(ctx, updateCmd)
+	_, err = cmdHandler.Handle(ctx, updateCmd)
 	if err != nil {
 		t.Fatalf("Failed to update team: %v", err)
 	}
@@ -17,14 +17,14 @@ func TestUpdateTeamName(t *testing.T) {
 	defer testDB.Cleanup()
 
 	ctx := context.Background()
-	eventStore := newTestEventStore(testDB.DB)
+	eventStore := newTestEventStore(t, testDB)
 	cmdHandler := commands.NewHandler(eventStore)
 	repo := projections.NewRepository(testDB.DB)
 
 	channelID := "C123456789"
 	registerCmd := mustRegisterTeam("general", channelID)
 
-	err := cmdHandler.Handle(ctx, registerCmd)
+	_, err := cmdHandler.Handle(ctx, registerCmd)
 	if err != nil {
 		t.Fatalf("Failed to register team: %v", err)
 	}
@@ -48,7 +48,7 @@ func TestUpdateTeamName(t *testing.T) {
 
 	updateCmd := mustUpdateTeam(teamID, "Product Team", channelID)
 
-	err = cmdHandler.Handle(ctx, updateCmd)
+	_, err = cmdHandler.Handle(ctx, updateCmd)
 	if err != nil {
 		t.Fatalf("Failed to update team name: %v", err)
 	}
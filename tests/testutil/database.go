@@ -85,12 +85,39 @@ func (tdb *TestDB) RunMigrations() {
 		timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
 		metadata JSONB,
 		version INTEGER NOT NULL,
-		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		position BIGINT NOT NULL,
+		schema_version INTEGER NOT NULL DEFAULT 1,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		CONSTRAINT events_aggregate_version_unique UNIQUE (aggregate_id, version)
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_events_aggregate_id ON events(aggregate_id);
 	CREATE INDEX IF NOT EXISTS idx_events_type ON events(type);
 	CREATE INDEX IF NOT EXISTS idx_events_timestamp ON events(timestamp);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_events_position ON events(position);
+	CREATE INDEX IF NOT EXISTS idx_events_correlation_id ON events((metadata->>'correlation_id'));
+
+	CREATE TABLE IF NOT EXISTS snapshots (
+		aggregate_id VARCHAR(255) PRIMARY KEY,
+		aggregate_type VARCHAR(255) NOT NULL,
+		version INTEGER NOT NULL,
+		schema_version INTEGER NOT NULL,
+		state JSONB NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS idempotency_keys (
+		key VARCHAR(255) PRIMARY KEY,
+		token VARCHAR(64),
+		fingerprint VARCHAR(64) NOT NULL,
+		status_code INTEGER,
+		headers JSONB,
+		body BYTEA,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
 	`
 
 	_, err := tdb.DB.Exec(eventStoreMigration)
@@ -105,7 +132,10 @@ func (tdb *TestDB) RunMigrations() {
 		name VARCHAR(255) NOT NULL,
 		slack_channel VARCHAR(255) NOT NULL,
 		created_at TIMESTAMP WITH TIME ZONE NOT NULL,
-		updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		health VARCHAR(16),
+		health_reason TEXT,
+		health_changed_at TIMESTAMP WITH TIME ZONE
 	);
 
 	CREATE TABLE IF NOT EXISTS status_updates (
@@ -114,12 +144,83 @@ func (tdb *TestDB) RunMigrations() {
 		content TEXT NOT NULL,
 		author VARCHAR(255) NOT NULL,
 		slack_user VARCHAR(255) NOT NULL,
-		created_at TIMESTAMP WITH TIME ZONE NOT NULL
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		edited_at TIMESTAMP WITH TIME ZONE,
+		slack_channel VARCHAR(255),
+		slack_ts VARCHAR(255),
+		slack_thread_ts VARCHAR(255),
+		slack_permalink TEXT,
+		format VARCHAR(16) NOT NULL DEFAULT 'text',
+		done TEXT,
+		next TEXT,
+		blockers TEXT,
+		health VARCHAR(16)
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_status_updates_team_id ON status_updates(team_id);
 	CREATE INDEX IF NOT EXISTS idx_status_updates_created_at ON status_updates(created_at DESC);
 	CREATE INDEX IF NOT EXISTS idx_status_updates_team_created ON status_updates(team_id, created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_status_updates_slack_ts ON status_updates(team_id, slack_ts);
+	CREATE INDEX IF NOT EXISTS idx_status_updates_blocked ON status_updates(created_at DESC) WHERE blockers IS NOT NULL;
+
+	CREATE TABLE IF NOT EXISTS status_update_edits (
+		event_id VARCHAR(255) PRIMARY KEY,
+		update_id VARCHAR(255) NOT NULL REFERENCES status_updates(update_id),
+		team_id VARCHAR(255) NOT NULL,
+		previous_content TEXT NOT NULL,
+		content TEXT NOT NULL,
+		edited_by VARCHAR(255) NOT NULL,
+		edited_at TIMESTAMP WITH TIME ZONE NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_status_update_edits_update ON status_update_edits(update_id, edited_at);
+
+	CREATE TABLE IF NOT EXISTS update_comments (
+		comment_id VARCHAR(255) PRIMARY KEY,
+		update_id VARCHAR(255) NOT NULL REFERENCES status_updates(update_id),
+		team_id VARCHAR(255) NOT NULL,
+		content TEXT NOT NULL,
+		author VARCHAR(255) NOT NULL,
+		slack_user VARCHAR(255),
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		slack_channel VARCHAR(255),
+		slack_ts VARCHAR(255),
+		slack_permalink TEXT
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_update_comments_update ON update_comments(update_id, created_at);
+
+	CREATE TABLE IF NOT EXISTS team_health_history (
+		event_id VARCHAR(255) PRIMARY KEY,
+		team_id VARCHAR(255) NOT NULL REFERENCES teams(team_id),
+		health VARCHAR(16) NOT NULL,
+		previous_health VARCHAR(16),
+		reason TEXT,
+		changed_by VARCHAR(255),
+		changed_at TIMESTAMP WITH TIME ZONE NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_team_health_history_team ON team_health_history(team_id, changed_at);
+
+	CREATE TABLE IF NOT EXISTS checkpoints (
+		projection VARCHAR(255) PRIMARY KEY,
+		position BIGINT NOT NULL,
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS dead_letters (
+		projection VARCHAR(255) NOT NULL,
+		event_id VARCHAR(255) NOT NULL,
+		position BIGINT NOT NULL,
+		event_type VARCHAR(255) NOT NULL,
+		error TEXT NOT NULL,
+		attempts INTEGER NOT NULL,
+		status VARCHAR(20) NOT NULL,
+		next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		PRIMARY KEY (projection, event_id)
+	);
 	`
 
 	_, err = tdb.DB.Exec(projectionsMigration)